@@ -0,0 +1,304 @@
+//nolint:forbidigo
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+	"github.com/mgutz/ansi"
+	"github.com/szkiba/create-k6-extension/scaffold"
+)
+
+type addition string
+
+const (
+	additionClass  addition = "class"
+	additionModule addition = "module"
+
+	declarationFile = "index.d.ts"
+)
+
+func addCommand(ctx context.Context, rt *runtime, args []string) error {
+	cmd := commands()["add"]
+	flags := subcommandFlagset(cmd, rt)
+
+	opts := new(options)
+
+	flags.StringVar(&opts.Dir, "dir", ".", "extension directory")
+	flags.StringVar(&opts.Name, "name", "", "extension name (default: guessed from directory)")
+	flags.BoolVar(&opts.debug, "debug", false, "enable debug output")
+
+	if err := rt.parse(flags, args); err != nil {
+		return err
+	}
+
+	rt.prerequisite()
+
+	if flags.NArg() != 3 {
+		flags.Usage()
+
+		return fmt.Errorf("%w: expected class or module and a name", errMissingArg)
+	}
+
+	what, name := addition(flags.Arg(1)), flags.Arg(2)
+
+	if err := what.validate(name); err != nil {
+		return err
+	}
+
+	abs, err := filepath.Abs(opts.Dir)
+	if err != nil {
+		return err
+	}
+
+	opts.Dir = abs
+	opts.Kind = scaffold.JavaScript
+	opts.GuessName()
+
+	if len(opts.Name) == 0 {
+		return fmt.Errorf("%w: %s", errMissingFlag, "name")
+	}
+
+	return newSession(ctx, opts, rt).add(what, name)
+}
+
+func (what addition) validate(name string) error {
+	var re *regexp.Regexp
+
+	switch what {
+	case additionClass:
+		re = reClassName
+	case additionModule:
+		re = reModuleName
+	default:
+		return fmt.Errorf("%w: %s", errUnknownAddition, what)
+	}
+
+	if !re.MatchString(name) {
+		return fmt.Errorf("%w: %s %s", errInvalidName, what, name)
+	}
+
+	return nil
+}
+
+func (what addition) declaration(name string) string {
+	if what == additionClass {
+		return fmt.Sprintf(`
+/**
+ * %s class.
+ */
+export declare class %s {
+  constructor();
+}
+`, name, name)
+	}
+
+	return fmt.Sprintf(`
+/**
+ * %s module.
+ */
+export declare namespace %s {
+  function hello(): string;
+}
+`, name, name)
+}
+
+func (what addition) skeleton(pkg, name string) string {
+	typ := strcase.ToLowerCamel(name)
+
+	if what == additionClass {
+		return fmt.Sprintf(`package %s
+
+// %sImpl implements the %s JavaScript class declared in %s.
+type %sImpl struct{}
+`, pkg, typ, name, declarationFile, typ)
+	}
+
+	return fmt.Sprintf(`package %s
+
+// %sModule implements the %s JavaScript module declared in %s.
+type %sModule struct{}
+
+func (m *%sModule) hello() string {
+	return "Hello, World!"
+}
+`, pkg, typ, name, declarationFile, typ, typ)
+}
+
+func (what addition) snippet(ext, name string) string {
+	if what == additionClass {
+		return fmt.Sprintf(`import { %s } from "k6/x/%s";
+
+export default function () {
+  const instance = new %s();
+
+  console.log(instance);
+}
+`, name, ext, name)
+	}
+
+	return fmt.Sprintf(`import { %s } from "k6/x/%s";
+
+export default function () {
+  console.log(%s.hello());
+}
+`, name, ext, name)
+}
+
+func (s *session) add(what addition, name string) error {
+	s.print("\n%s\n", ansi.Color(fmt.Sprintf("Adding %s %s", what, name), "yellow+b"))
+
+	declFile := filepath.Join(s.opts.Dir, declarationFile)
+	goFile := filepath.Join(s.opts.Dir, strcase.ToSnake(name)+".go")
+	jsFile := filepath.Join(s.opts.Dir, "test-"+strcase.ToKebab(name)+".js")
+
+	var original []byte
+
+	err := s.Step("Check extension", func() error {
+		var cerr error
+
+		original, cerr = s.checkExtension(declFile, goFile, jsFile)
+
+		return cerr
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = s.addFiles(what, name, goFile, jsFile); err != nil {
+		s.rollback(declFile, original, goFile, jsFile)
+
+		return err
+	}
+
+	s.print("\n%s\n", ansi.Color(fmt.Sprintf("The %s %s has been added.", what, name), "green"))
+	s.print("Complete the declaration in:\n  %s\n", ansi.Color(declarationFile, "yellow"))
+	s.print("Implement the go code in:\n  %s\n", ansi.Color(filepath.Base(goFile), "yellow"))
+	s.print("Test the new API with the following command:\n  %s\n",
+		ansi.Color("./k6 run "+filepath.Base(jsFile), "yellow"),
+	)
+
+	return nil
+}
+
+// checkExtension checks that the directory contains a JavaScript extension
+// and that none of the files to be created exists yet.
+// It returns the original content of the declaration file.
+func (s *session) checkExtension(declFile string, files ...string) ([]byte, error) {
+	bin, err := s.Output(s.opts.Dir, "go", "list", "-m")
+	if err != nil {
+		return nil, err
+	}
+
+	module := strings.TrimSpace(string(bin))
+
+	if moduleOptions(module).Kind != scaffold.JavaScript {
+		return nil, fmt.Errorf("%w: %s", errNotJavaScript, module)
+	}
+
+	original, err := os.ReadFile(filepath.Clean(declFile))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errNotJavaScript, err.Error())
+	}
+
+	for _, file := range files {
+		if _, err := os.Stat(file); err == nil {
+			return nil, fmt.Errorf("%w: %s", os.ErrExist, file)
+		}
+	}
+
+	return original, nil
+}
+
+func (s *session) addFiles(what addition, name, goFile, jsFile string) error {
+	err := s.Step("Add declaration", func() error {
+		return s.addDeclaration(what.declaration(name))
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = s.Step("Generate sources", s.runGoGenerate); err != nil {
+		return err
+	}
+
+	err = s.Step("Create go skeleton", func() error {
+		bin, oerr := s.Output(s.opts.Dir, "go", "list", "-f", "{{.Name}}", ".")
+		if oerr != nil {
+			return oerr
+		}
+
+		pkg := strings.TrimSpace(string(bin))
+
+		return writeNew(goFile, what.skeleton(pkg, name))
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.Step("Create test script", func() error {
+		return writeNew(jsFile, what.snippet(s.opts.Name, name))
+	})
+}
+
+// rollback restores the declaration file and removes the created files (which did not exist before),
+// so the failed addition leaves the extension unchanged.
+// The sources are regenerated from the restored declaration file.
+func (s *session) rollback(declFile string, original []byte, created ...string) {
+	_ = os.WriteFile(declFile, original, 0o600)
+
+	for _, file := range created {
+		_ = os.Remove(file)
+	}
+
+	_ = s.runGoGenerate()
+}
+
+func (s *session) addDeclaration(decl string) error {
+	filename := filepath.Join(s.opts.Dir, declarationFile)
+
+	file, err := os.OpenFile(filepath.Clean(filename), os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+
+	if _, err = file.WriteString(decl); err != nil {
+		_ = file.Close()
+
+		return err
+	}
+
+	return file.Close()
+}
+
+func writeNew(filename string, content string) error {
+	file, err := os.OpenFile(filepath.Clean(filename), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+
+	if _, err = file.WriteString(content); err != nil {
+		_ = file.Close()
+
+		return err
+	}
+
+	return file.Close()
+}
+
+var (
+	reClassName  = regexp.MustCompile("^[A-Z][A-Za-z0-9]{0,63}$") //nolint:gochecknoglobals
+	reModuleName = regexp.MustCompile("^[a-z][a-z0-9]{2,31}$")    //nolint:gochecknoglobals
+)
+
+var (
+	errUnknownAddition = errors.New("unknown addition, expected class or module")
+	errInvalidName     = errors.New("invalid name")
+	errNotJavaScript   = errors.New("not a JavaScript extension")
+)
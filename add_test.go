@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/szkiba/create-k6-extension/scaffold/scaffoldtest"
+)
+
+func Test_addition_validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		what    addition
+		name    string
+		wantErr error
+	}{
+		{what: additionClass, name: "Greeter"},
+		{what: additionClass, name: "greeter", wantErr: errInvalidName},
+		{what: additionClass, name: "9Lives", wantErr: errInvalidName},
+		{what: additionModule, name: "crypto"},
+		{what: additionModule, name: "Crypto", wantErr: errInvalidName},
+		{what: additionModule, name: "ab", wantErr: errInvalidName},
+		{what: "function", name: "greet", wantErr: errUnknownAddition},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(string(tt.what)+"_"+tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.what.validate(tt.name)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+const addFixtureDeclaration = "declare module \"k6/x/foo\" {}\n"
+
+// addFixture returns a session on a JavaScript extension containing only index.d.ts.
+func addFixture(t *testing.T, module string) (*session, *scaffoldtest.Executor) {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, declarationFile), []byte(addFixtureDeclaration), 0o600))
+
+	rt, _, _ := testRuntime(nil)
+	exec := rt.executor.(*scaffoldtest.Executor) //nolint:forcetypeassert
+
+	exec.Outputs["^go list -m$"] = module + "\n"
+	exec.Outputs["^go list -f"] = "foo\n"
+
+	opts := &options{}
+	opts.Dir = dir
+	opts.Name = "foo"
+
+	return newSession(context.Background(), opts, rt), exec
+}
+
+func readFile(t *testing.T, filename string) string {
+	t.Helper()
+
+	content, err := os.ReadFile(filepath.Clean(filename))
+	require.NoError(t, err)
+
+	return string(content)
+}
+
+func Test_session_add_class(t *testing.T) {
+	t.Parallel()
+
+	s, exec := addFixture(t, "github.com/acme/xk6-foo")
+
+	require.NoError(t, s.add(additionClass, "Greeter"))
+
+	assert.Equal(t, addFixtureDeclaration+`
+/**
+ * Greeter class.
+ */
+export declare class Greeter {
+  constructor();
+}
+`, readFile(t, filepath.Join(s.opts.Dir, declarationFile)))
+
+	assert.Equal(t, `package foo
+
+// greeterImpl implements the Greeter JavaScript class declared in index.d.ts.
+type greeterImpl struct{}
+`, readFile(t, filepath.Join(s.opts.Dir, "greeter.go")))
+
+	assert.Equal(t, `import { Greeter } from "k6/x/foo";
+
+export default function () {
+  const instance = new Greeter();
+
+  console.log(instance);
+}
+`, readFile(t, filepath.Join(s.opts.Dir, "test-greeter.js")))
+
+	assert.Equal(t, []string{"go list -m", "go generate ./...", "go list -f {{.Name}} ."}, exec.Lines())
+}
+
+func Test_session_add_module(t *testing.T) {
+	t.Parallel()
+
+	s, _ := addFixture(t, "github.com/acme/xk6-foo")
+
+	require.NoError(t, s.add(additionModule, "crypto"))
+
+	assert.Contains(t, readFile(t, filepath.Join(s.opts.Dir, declarationFile)),
+		"export declare namespace crypto {\n  function hello(): string;\n}\n")
+
+	assert.Equal(t, `package foo
+
+// cryptoModule implements the crypto JavaScript module declared in index.d.ts.
+type cryptoModule struct{}
+
+func (m *cryptoModule) hello() string {
+	return "Hello, World!"
+}
+`, readFile(t, filepath.Join(s.opts.Dir, "crypto.go")))
+
+	assert.Equal(t, `import { crypto } from "k6/x/foo";
+
+export default function () {
+  console.log(crypto.hello());
+}
+`, readFile(t, filepath.Join(s.opts.Dir, "test-crypto.js")))
+}
+
+func Test_session_add_existing(t *testing.T) {
+	t.Parallel()
+
+	for _, existing := range []string{"greeter.go", "test-greeter.js"} {
+		existing := existing
+
+		t.Run(existing, func(t *testing.T) {
+			t.Parallel()
+
+			s, exec := addFixture(t, "github.com/acme/xk6-foo")
+
+			filename := filepath.Join(s.opts.Dir, existing)
+
+			require.NoError(t, os.WriteFile(filename, []byte("keep me\n"), 0o600))
+
+			require.ErrorIs(t, s.add(additionClass, "Greeter"), os.ErrExist)
+
+			assert.Equal(t, "keep me\n", readFile(t, filename))
+			assert.Equal(t, addFixtureDeclaration, readFile(t, filepath.Join(s.opts.Dir, declarationFile)))
+			assert.NotContains(t, exec.Lines(), "go generate ./...")
+		})
+	}
+}
+
+func Test_session_add_rollback(t *testing.T) {
+	t.Parallel()
+
+	s, exec := addFixture(t, "github.com/acme/xk6-foo")
+
+	errFake := errors.New("fake failure")
+
+	exec.Failures["^go list -f"] = errFake
+
+	require.ErrorIs(t, s.add(additionClass, "Greeter"), errFake)
+
+	assert.Equal(t, addFixtureDeclaration, readFile(t, filepath.Join(s.opts.Dir, declarationFile)))
+	assert.NoFileExists(t, filepath.Join(s.opts.Dir, "greeter.go"))
+	assert.NoFileExists(t, filepath.Join(s.opts.Dir, "test-greeter.js"))
+
+	// the sources are regenerated from the restored declaration
+	assert.Equal(t, "go generate ./...", exec.Lines()[len(exec.Lines())-1])
+}
+
+func Test_session_add_notJavaScript(t *testing.T) {
+	t.Parallel()
+
+	s, _ := addFixture(t, "github.com/acme/xk6-output-foo")
+
+	require.ErrorIs(t, s.add(additionClass, "Greeter"), errNotJavaScript)
+	assert.Equal(t, addFixtureDeclaration, readFile(t, filepath.Join(s.opts.Dir, declarationFile)))
+
+	s, _ = addFixture(t, "github.com/acme/xk6-foo")
+
+	require.NoError(t, os.Remove(filepath.Join(s.opts.Dir, declarationFile)))
+	require.ErrorIs(t, s.add(additionClass, "Greeter"), errNotJavaScript)
+	assert.NoFileExists(t, filepath.Join(s.opts.Dir, "greeter.go"))
+}
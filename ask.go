@@ -6,27 +6,27 @@ import (
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/AlecAivazis/survey/v2/terminal"
-	"github.com/go-playground/validator/v10"
 	"github.com/mgutz/ansi"
+	"github.com/szkiba/create-k6-extension/scaffold"
 )
 
-func ask(opts *options, stdio *terminal.Stdio) (bool, error) {
-	a := newAsker(opts, stdio)
+func ask(opts *options, rt *runtime) (bool, error) {
+	a := newAsker(opts, rt)
 
 	return a.askLoop()
 }
 
 type asker struct {
 	*terminal.Stdio
-	opts     *options
-	validate *validator.Validate
+	opts      *options
+	templates *scaffold.Prefetcher
 }
 
-func newAsker(opts *options, stdio *terminal.Stdio) *asker {
+func newAsker(opts *options, rt *runtime) *asker {
 	return &asker{
-		Stdio:    stdio,
-		opts:     opts,
-		validate: validator.New(),
+		Stdio:     rt.Stdio,
+		opts:      opts,
+		templates: rt.templates,
 	}
 }
 
@@ -35,46 +35,62 @@ func (a *asker) print(format string, args ...any) {
 }
 
 func (a *asker) askKind() error {
-	a.opts.guessKind()
+	a.opts.GuessKind()
 
 	//nolint:lll
 	prompt := &survey.Select{
 		Message: "Extension type:",
-		Options: []string{string(javascript), string(output)},
+		Options: []string{string(scaffold.JavaScript), string(scaffold.Output)},
 		Help:    "k6 supports two ways to extend its native functionality. Select JavaScript to extend the JavaScript APIs available to your test scripts. Select Output to send metrics to a custom file format or service.",
 		Default: string(a.opts.Kind),
 	}
 
-	return a.ask(&a.opts.Kind, prompt)
+	kind := string(a.opts.Kind)
+
+	if err := a.ask(&kind, prompt); err != nil {
+		return err
+	}
+
+	a.opts.Kind = scaffold.Kind(kind)
+	a.templates.Start(a.opts.Kind)
+
+	return nil
 }
 
 func (a *asker) askName() error {
-	a.opts.guessName()
+	a.opts.GuessName()
 
 	var help string
 
-	if a.opts.Kind == javascript {
+	if a.opts.Kind == scaffold.JavaScript {
 		help = "The part of the JavaScript module name after the k6/x/ prefix."
 	} else {
 		help = "The name to pass to the k6 run --out flag."
 	}
 
-	return a.ask(
+	err := a.ask(
 		&a.opts.Name,
 		&survey.Input{
 			Message: "Extension name:",
 			Help:    help,
 			Default: a.opts.Name,
 		},
-		a.expect(
-			"alphanum,min=3,max=32,lowercase",
-			"Minimum 3 maximum 32 lower case alphanumeric characters are required",
-		),
+		check(scaffold.CheckName),
 	)
+	if err != nil {
+		return err
+	}
+
+	// a name used by a known extension is allowed (for example for a fork), but worth a warning
+	if err := a.opts.catalog.Check(a.opts.Kind, a.opts.Name); err != nil {
+		a.print("%s\n", ansi.Color("warning: "+err.Error(), "yellow"))
+	}
+
+	return nil
 }
 
 func (a *asker) askDir() error {
-	a.opts.guessDir()
+	a.opts.GuessDir()
 
 	return a.ask(
 		&a.opts.Dir,
@@ -98,6 +114,27 @@ func (a *asker) askSummary() error {
 	)
 }
 
+func (a *asker) askCategories() error {
+	defaults := make([]string, 0, len(a.opts.Categories))
+
+	// survey rejects unknown default options, the flag value is validated after the questions
+	for _, category := range a.opts.Categories {
+		if scaffold.CheckCategories([]string{category}) == nil {
+			defaults = append(defaults, category)
+		}
+	}
+
+	return a.ask(
+		&a.opts.Categories,
+		&survey.MultiSelect{
+			Message: "Registry categories:",
+			Options: scaffold.Categories(),
+			Help:    "The categories of the extension in the k6 extension registry. Without selection, the misc category is used.",
+			Default: defaults,
+		},
+	)
+}
+
 func (a *asker) askConfirm() (bool, error) {
 	prompt := &survey.Confirm{
 		Message: "Are the above answers correct?",
@@ -136,7 +173,7 @@ func (a *asker) askNoGitOrigin() error {
 }
 
 func (a *asker) askUseGitHub() error {
-	a.opts.guessUseGitHub()
+	a.opts.GuessUseGitHub()
 
 	if a.opts.NoGitInit {
 		return nil
@@ -146,33 +183,61 @@ func (a *asker) askUseGitHub() error {
 	return a.ask(
 		&a.opts.UseGitHub,
 		&survey.Confirm{
-			Message: "Host the repository on GitHub:",
+			Message: "Host the repository on a hosting service:",
 			Default: a.opts.UseGitHub,
-			Help:    "The easiest way to host your extension's repository is to use GitHub. Choose `n` if you want to host the repository elsewhere.",
+			Help:    "The easiest way to host your extension's repository is to use a hosting service like GitHub, GitLab, Gitea or Bitbucket. Choose `n` if you want to host the repository elsewhere.",
 		},
 	)
 }
 
+func (a *asker) askRepoHost() error {
+	if !a.opts.UseGitHub {
+		return nil
+	}
+
+	if len(a.opts.RepoHost) == 0 {
+		a.opts.RepoHost = a.opts.Host().Domain
+	}
+
+	//nolint:lll
+	return a.ask(
+		&a.opts.RepoHost,
+		&survey.Input{
+			Message: "Repository host:",
+			Help:    "The host name of the hosting service, like github.com, gitlab.com or bitbucket.org. Prefix a self-managed host with the provider (github, gitlab, gitea or bitbucket) if the host name does not contain it, like gitlab:git.example.com.",
+			Default: a.opts.RepoHost,
+		},
+		check(scaffold.CheckRepoHost),
+	)
+}
+
 func (a *asker) askRepoOwner() error {
-	a.opts.guessUseGitHub()
+	a.opts.GuessUseGitHub()
 
 	if !a.opts.UseGitHub {
 		return nil
 	}
 
+	host := a.opts.Host()
+
+	help := "The user or organization that owns the repository."
+	if host.NestedOwner() {
+		help = "The user or group that owns the repository. Nested groups are separated by slashes, like org/team/subteam."
+	}
+
 	return a.ask(
 		&a.opts.RepoOwner,
 		&survey.Input{
-			Message: "GitHub repository owner:",
-			Help:    "GitHub user or organization that owns the repository.",
+			Message: host.Title() + " repository owner:",
+			Help:    help,
 			Default: a.opts.RepoOwner,
 		},
-		survey.Required,
+		check(func(owner string) error { return scaffold.CheckRepoOwner(host, owner) }),
 	)
 }
 
 func (a *asker) askGitOrigin() error {
-	a.opts.guessGitOrigin()
+	a.opts.GuessGitOrigin()
 
 	if a.opts.NoGitInit || a.opts.NoGitOrigin {
 		return nil
@@ -185,19 +250,19 @@ func (a *asker) askGitOrigin() error {
 			Help:    "The git origin URL as you want to use it in the `git remote add` command.",
 			Default: a.opts.GitOrigin,
 		},
-		survey.Required,
+		check(scaffold.CheckGitOrigin),
 	)
 }
 
 func (a *asker) askRepoName() error {
-	a.opts.guessUseGitHub()
-	a.opts.guessRepoName()
+	a.opts.GuessUseGitHub()
+	a.opts.GuessRepoName()
 
 	if !a.opts.UseGitHub {
 		return nil
 	}
 
-	prefix := a.opts.Kind.repoNamePrefix()
+	prefix := a.opts.Kind.RepoNamePrefix()
 
 	if len(a.opts.RepoName) == 0 {
 		a.opts.RepoName = prefix + a.opts.Name
@@ -206,15 +271,11 @@ func (a *asker) askRepoName() error {
 	return a.ask(
 		&a.opts.RepoName,
 		&survey.Input{
-			Message: "GitHub repository name:",
-			Help:    "The name of the GitHub repository. The name must start with the " + prefix + " prefix.",
+			Message: a.opts.Host().Title() + " repository name:",
+			Help:    "The name of the repository. The name must start with the " + prefix + " prefix.",
 			Default: a.opts.RepoName,
 		},
-		survey.Required,
-		a.expect(
-			"startswith="+prefix,
-			"The name must start with "+ansi.Color(prefix, "+b"),
-		),
+		check(func(name string) error { return scaffold.CheckRepoName(a.opts.Kind, name) }),
 	)
 }
 
@@ -239,7 +300,7 @@ func (a *asker) askRepoProtocol() error {
 }
 
 func (a *asker) askGoModule() error {
-	a.opts.guessGoModule()
+	a.opts.GuessGoModule()
 
 	return a.ask(
 		&a.opts.GoModule,
@@ -248,12 +309,12 @@ func (a *asker) askGoModule() error {
 			Help:    "The go module path to use for the `go mod init` command.",
 			Default: a.opts.GoModule,
 		},
-		survey.Required,
+		check(scaffold.CheckGoModule),
 	)
 }
 
 func (a *asker) askGoPackage() error {
-	a.opts.guessGoPackage()
+	a.opts.GuessGoPackage()
 
 	return a.ask(
 		&a.opts.GoPackage,
@@ -262,7 +323,7 @@ func (a *asker) askGoPackage() error {
 			Help:    "The go package name used in the generated go source code.",
 			Default: a.opts.GoPackage,
 		},
-		survey.Required,
+		check(scaffold.CheckGoPackage),
 	)
 }
 
@@ -272,10 +333,10 @@ func (a *asker) askNoInstall() error {
 	}
 
 	return a.ask(
-		&a.opts.noInstall,
+		&a.opts.NoInstall,
 		&survey.Confirm{
 			Message: "Disable xk6 install:",
-			Default: a.opts.noInstall,
+			Default: a.opts.NoInstall,
 			Help:    "xk6 is a k6 extension development tool that is essential for extension development.",
 		},
 	)
@@ -292,7 +353,7 @@ func (a *asker) askAll() error {
 				return err
 			}
 
-			a.opts.update()
+			a.opts.Update()
 		}
 
 		return nil
@@ -301,7 +362,7 @@ func (a *asker) askAll() error {
 	var err error
 
 	err = section("General options",
-		a.askKind, a.askName, a.askSummary, a.askDir, a.askNoInstall,
+		a.askKind, a.askName, a.askSummary, a.askCategories, a.askDir, a.askNoInstall,
 	)
 	if err != nil {
 		return err
@@ -309,7 +370,7 @@ func (a *asker) askAll() error {
 
 	err = section("Git repository",
 		a.askNoGitInit,
-		a.askUseGitHub, a.askRepoOwner, a.askRepoName,
+		a.askUseGitHub, a.askRepoHost, a.askRepoOwner, a.askRepoName,
 		a.askNoGitOrigin,
 		a.askRepoProtocol,
 		a.askGitOrigin,
@@ -326,7 +387,7 @@ func (a *asker) askAll() error {
 		return err
 	}
 
-	a.opts.update()
+	a.opts.Update()
 
 	return nil
 }
@@ -349,32 +410,24 @@ func (a *asker) askLoop() (bool, error) {
 	}
 
 	if err != nil {
-		if err.Error() == "interrupt" {
+		if errors.Is(err, terminal.InterruptErr) {
 			return false, nil
 		}
 
 		return false, err
 	}
 
-	a.opts.guessPrimaryClass()
+	a.opts.GuessPrimaryClass()
 
 	return true, nil
 }
 
-func (a *asker) expect(tag, msg string) survey.Validator {
+// check returns a survey validator from a scaffold option check, so both modes validate the options the same way.
+func check(fn func(string) error) survey.Validator {
 	return func(value interface{}) error {
-		err := a.validate.Var(value, tag)
-		if err == nil {
-			return nil
-		}
-
-		var verr validator.ValidationErrors
-
-		if !errors.As(err, &verr) {
-			return err
-		}
+		str, _ := value.(string)
 
-		return errors.New(msg)
+		return fn(str)
 	}
 }
 
@@ -386,6 +439,7 @@ func (a *asker) ask(
 	return survey.AskOne(
 		prompt,
 		response,
+		survey.WithStdio(a.In, a.Out, a.Err),
 		survey.WithValidator(survey.ComposeValidators(validators...)),
 	)
 }
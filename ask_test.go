@@ -0,0 +1,297 @@
+//go:build !windows
+
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AlecAivazis/survey/v2/terminal"
+	expect "github.com/Netflix/go-expect"
+	pseudotty "github.com/creack/pty"
+	"github.com/hinshun/vt10x"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/szkiba/create-k6-extension/scaffold"
+	"github.com/szkiba/create-k6-extension/scaffold/scaffoldtest"
+)
+
+// keystrokes is a scripted interaction with the questionnaire running in a pseudo-terminal.
+type keystrokes func(c *expect.Console)
+
+// answer waits for the prompt and answers it with the given line.
+// If the prompt does not appear, the terminal is closed, so the questionnaire fails instead of hanging.
+func answer(c *expect.Console, prompt string, line string) {
+	if _, err := c.ExpectString(prompt); err != nil {
+		_ = c.Tty().Close()
+
+		return
+	}
+
+	_, _ = c.SendLine(line)
+}
+
+func newAskOptions(protocol string) *options {
+	opts := &options{installed: true}
+
+	opts.Kind = scaffold.JavaScript
+	opts.RepoProtocol = protocol
+
+	return opts
+}
+
+func runAskLoop(t *testing.T, opts *options, script keystrokes) (bool, error) {
+	t.Helper()
+
+	return runAskLoopWith(t, opts, nil, script)
+}
+
+func runAskLoopWith(t *testing.T, opts *options, templates *scaffold.Prefetcher, script keystrokes) (bool, error) {
+	t.Helper()
+
+	pty, tty, err := pseudotty.Open()
+	require.NoError(t, err)
+
+	console, err := expect.NewConsole(
+		expect.WithStdin(pty),
+		expect.WithStdout(vt10x.New(vt10x.WithWriter(tty))),
+		expect.WithCloser(pty, tty),
+		expect.WithDefaultTimeout(5*time.Second),
+	)
+	require.NoError(t, err)
+
+	defer console.Close() //nolint:errcheck
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		script(console)
+
+		_, _ = console.ExpectEOF()
+	}()
+
+	stdio := &terminal.Stdio{In: console.Tty(), Out: console.Tty(), Err: console.Tty()}
+
+	ok, err := ask(opts, &runtime{Stdio: stdio, templates: templates})
+
+	require.NoError(t, console.Tty().Close())
+
+	<-done
+
+	return ok, err
+}
+
+func answerGeneral(c *expect.Console, kindKeys string, name string) {
+	answer(c, "Extension type:", kindKeys)
+	answer(c, "Extension name:", name)
+	answer(c, "Short description:", "Test extension")
+	answer(c, "Registry categories:", "")
+	answer(c, "Directory name:", "")
+}
+
+func answerGitHub(c *expect.Console, owner string) {
+	answer(c, "Disable git repository initialization:", "")
+	answer(c, "Host the repository on a hosting service:", "y")
+	answer(c, "Repository host:", "")
+	answer(c, "GitHub repository owner:", owner)
+	answer(c, "GitHub repository name:", "")
+	answer(c, "Disable setting git origin:", "")
+	answer(c, "Choose git origin protocol:", "")
+	answer(c, "git origin URL:", "")
+}
+
+func answerGo(c *expect.Console) {
+	answer(c, "go module path:", "")
+	answer(c, "go package name:", "")
+}
+
+func Test_ask_javascript(t *testing.T) {
+	t.Parallel()
+
+	opts := newAskOptions("ssh")
+
+	ok, err := runAskLoop(t, opts, func(c *expect.Console) {
+		answerGeneral(c, "", "foo")
+		answerGitHub(c, "acme")
+		answerGo(c)
+		answer(c, "Are the above answers correct?", "y")
+	})
+
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	assert.Equal(t, scaffold.JavaScript, opts.Kind)
+	assert.Equal(t, "foo", opts.Name)
+	assert.Equal(t, "Test extension", opts.Summary)
+	assert.Equal(t, "xk6-foo", opts.Dir)
+	assert.Equal(t, "xk6-foo", opts.RepoName)
+	assert.Equal(t, "git@github.com:acme/xk6-foo.git", opts.GitOrigin)
+	assert.Equal(t, "github.com/acme/xk6-foo", opts.GoModule)
+	assert.Equal(t, "foo", opts.GoPackage)
+	assert.Equal(t, "Foo", opts.PrimaryClass)
+	assert.Equal(t, "XK6_FOO", opts.EnvPrefix)
+}
+
+func Test_ask_output(t *testing.T) {
+	t.Parallel()
+
+	opts := newAskOptions("ssh")
+
+	ok, err := runAskLoop(t, opts, func(c *expect.Console) {
+		answerGeneral(c, string(terminal.KeyArrowDown), "bar")
+		answerGitHub(c, "acme")
+		answerGo(c)
+		answer(c, "Are the above answers correct?", "y")
+	})
+
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	assert.Equal(t, scaffold.Output, opts.Kind)
+	assert.Equal(t, "bar", opts.Name)
+	assert.Equal(t, "xk6-output-bar", opts.Dir)
+	assert.Equal(t, "xk6-output-bar", opts.RepoName)
+	assert.Equal(t, "git@github.com:acme/xk6-output-bar.git", opts.GitOrigin)
+	assert.Equal(t, "github.com/acme/xk6-output-bar", opts.GoModule)
+	assert.Equal(t, "XK6_OUTPUT_BAR", opts.EnvPrefix)
+}
+
+func Test_ask_reask(t *testing.T) {
+	t.Parallel()
+
+	opts := newAskOptions("ssh")
+
+	ok, err := runAskLoop(t, opts, func(c *expect.Console) {
+		answerGeneral(c, "", "foo")
+		answer(c, "Disable git repository initialization:", "y")
+		answerGo(c)
+		answer(c, "Are the above answers correct?", "n")
+
+		answerGeneral(c, "", "")
+		answer(c, "Disable git repository initialization:", "n")
+		answer(c, "Host the repository on a hosting service:", "")
+		answer(c, "Disable setting git origin:", "y")
+		answerGo(c)
+		answer(c, "Are the above answers correct?", "y")
+	})
+
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	assert.Equal(t, "foo", opts.Name)
+	assert.False(t, opts.NoGitInit)
+	assert.True(t, opts.NoGitOrigin)
+	assert.False(t, opts.UseGitHub)
+	assert.Equal(t, "xk6-foo", opts.GoModule)
+}
+
+func Test_ask_interrupt(t *testing.T) {
+	t.Parallel()
+
+	opts := newAskOptions("")
+
+	ok, err := runAskLoop(t, opts, func(c *expect.Console) {
+		answer(c, "Extension type:", "")
+		_, _ = c.ExpectString("Extension name:")
+		_, _ = c.Send(string(terminal.KeyInterrupt))
+	})
+
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func Test_ask_prefetch(t *testing.T) {
+	t.Parallel()
+
+	exec := scaffoldtest.NewExecutor()
+	templates := scaffold.NewPrefetcher(exec)
+	opts := newAskOptions("")
+
+	ok, err := runAskLoopWith(t, opts, templates, func(c *expect.Console) {
+		answerGeneral(c, "", "foo")
+		answer(c, "Disable git repository initialization:", "y")
+		answerGo(c)
+		answer(c, "Are the above answers correct?", "n")
+
+		answerGeneral(c, string(terminal.KeyArrowDown), "")
+		answer(c, "Disable git repository initialization:", "")
+		answerGo(c)
+		answer(c, "Are the above answers correct?", "y")
+	})
+
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, scaffold.Output, opts.Kind)
+
+	templates.Close()
+
+	var clones []string
+
+	for _, line := range exec.Lines() {
+		if strings.HasPrefix(line, "git clone") {
+			clones = append(clones, line)
+		}
+	}
+
+	require.Len(t, clones, 2)
+	assert.Contains(t, strings.Join(clones, "\n"), "xk6-template-javascript.git")
+	assert.Contains(t, strings.Join(clones, "\n"), "xk6-template-output.git")
+}
+
+func Test_ask_name_collision(t *testing.T) {
+	t.Parallel()
+
+	opts := newAskOptions("")
+	opts.catalog = scaffold.BundledCatalog()
+
+	var warned bool
+
+	ok, err := runAskLoop(t, opts, func(c *expect.Console) {
+		answer(c, "Extension type:", "")
+		answer(c, "Extension name:", "sql")
+
+		_, werr := c.ExpectString("warning: name collision: sql is already used by github.com/grafana/xk6-sql")
+		warned = werr == nil
+
+		answer(c, "Short description:", "")
+		answer(c, "Registry categories:", "")
+		answer(c, "Directory name:", "")
+		answer(c, "Disable git repository initialization:", "y")
+		answerGo(c)
+		answer(c, "Are the above answers correct?", "y")
+	})
+
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, warned)
+	assert.Equal(t, "sql", opts.Name)
+}
+
+func Test_ask_gitlab(t *testing.T) {
+	t.Parallel()
+
+	opts := newAskOptions("https")
+
+	ok, err := runAskLoop(t, opts, func(c *expect.Console) {
+		answerGeneral(c, "", "foo")
+		answer(c, "Disable git repository initialization:", "")
+		answer(c, "Host the repository on a hosting service:", "y")
+		answer(c, "Repository host:", "gitlab:git.example.com")
+		answer(c, "GitLab repository owner:", "acme/k6/team")
+		answer(c, "GitLab repository name:", "")
+		answer(c, "Disable setting git origin:", "")
+		answer(c, "Choose git origin protocol:", "")
+		answer(c, "git origin URL:", "")
+		answerGo(c)
+		answer(c, "Are the above answers correct?", "y")
+	})
+
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	assert.Equal(t, "https://git.example.com/acme/k6/team/xk6-foo.git", opts.GitOrigin)
+	assert.Equal(t, "git.example.com/acme/k6/team/xk6-foo", opts.GoModule)
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/spf13/pflag"
+)
+
+type command struct {
+	usage string
+	help  string
+	run   func(ctx context.Context, rt *runtime, args []string) error
+}
+
+func commands() map[string]*command {
+	return map[string]*command{
+		"add": {
+			usage: "add [flags] class|module <name>",
+			help:  "add a JavaScript class or module to an existing extension",
+			run:   addCommand,
+		},
+		"doctor": {
+			usage: "doctor [flags]",
+			help:  "check the development environment",
+			run:   doctorCommand,
+		},
+		"registry-entry": {
+			usage: "registry-entry [flags]",
+			help:  "regenerate the k6 extension registry metadata of an existing extension",
+			run:   registryEntryCommand,
+		},
+		"rename": {
+			usage: "rename [flags] <old-name> <new-name>",
+			help:  "rename an existing extension",
+			run:   renameCommand,
+		},
+	}
+}
+
+func lookupCommand(args []string) (*command, bool) {
+	if len(args) < 2 {
+		return nil, false
+	}
+
+	cmd, found := commands()[args[1]]
+
+	return cmd, found
+}
+
+func commandUsage(out io.Writer) {
+	cmds := commands()
+
+	names := make([]string, 0, len(cmds))
+	for name := range cmds {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	fmt.Fprintf(out, "\nCommands:\n")
+
+	for _, name := range names {
+		fmt.Fprintf(out, "  %-15s %s\n", name, cmds[name].help)
+	}
+}
+
+func subcommandFlagset(cmd *command, rt *runtime) *pflag.FlagSet {
+	flags := pflag.NewFlagSet(_appname, pflag.ContinueOnError)
+
+	flags.StringVar(&rt.colorMode, "color", colorAuto, "colored output (auto, always or never)")
+
+	flags.Usage = func() {
+		fmt.Fprintf(rt.Err,
+			"Usage: %s %s\n\nFlags:\n%s",
+			_appname,
+			cmd.usage,
+			flags.FlagUsages(),
+		)
+	}
+
+	return flags
+}
+
+func runCommand(ctx context.Context, rt *runtime, cmd *command) {
+	err := cmd.run(ctx, rt, rt.args[1:])
+	if errors.Is(err, pflag.ErrHelp) {
+		return
+	}
+
+	if err != nil {
+		rt.fail(err)
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/AlecAivazis/survey/v2/terminal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/szkiba/create-k6-extension/scaffold"
+	"github.com/szkiba/create-k6-extension/scaffold/scaffoldtest"
+)
+
+const fakeTemplateModule = "github.com/szkiba/xk6-template-javascript"
+
+func fakeCreateRuntime(t *testing.T) (*runtime, *scaffoldtest.Executor) {
+	t.Helper()
+
+	exec := scaffoldtest.NewExecutor()
+
+	exec.Outputs["^go list -m$"] = fakeTemplateModule + "\n"
+	exec.Outputs["^go version$"] = "go version go1.21.5 linux/amd64\n"
+	exec.Hooks["^git clone"] = func(_ string, args []string) error {
+		dir := args[len(args)-1]
+
+		gomod := "module " + fakeTemplateModule + "\n\ngo 1.21\n\nrequire go.k6.io/k6 v0.48.0\n"
+
+		return os.WriteFile(filepath.Join(dir, "go.mod"), []byte(gomod), 0o600) //nolint:forbidigo
+	}
+
+	var out bytes.Buffer
+
+	rt := &runtime{
+		Stdio:    &terminal.Stdio{Out: bufferWriter{&out}, Err: &out},
+		executor: exec,
+	}
+
+	return rt, exec
+}
+
+func fakeCreateOptions(t *testing.T) *options {
+	t.Helper()
+
+	opts := new(options)
+
+	opts.Kind = scaffold.JavaScript
+	opts.Name = "foo"
+	opts.RepoOwner = "acme"
+	opts.RepoProtocol = "ssh"
+	opts.Dir = filepath.Join(t.TempDir(), "xk6-foo")
+
+	opts.Guess()
+	opts.Update()
+
+	return opts
+}
+
+func Test_create(t *testing.T) {
+	t.Parallel()
+
+	rt, exec := fakeCreateRuntime(t)
+	opts := fakeCreateOptions(t)
+
+	opts.installed = true
+
+	require.NoError(t, create(context.Background(), opts, rt))
+
+	assert.NotContains(t, exec.Lines(), "go install go.k6.io/xk6/cmd/xk6@latest")
+
+	out := rt.Out.(bufferWriter).String() //nolint:forcetypeassert
+
+	assert.Contains(t, out, "Generate sources done")
+	assert.Contains(t, out, "xk6 build --with github.com/acme/xk6-foo=.")
+}
+
+func Test_create_failure(t *testing.T) {
+	t.Parallel()
+
+	rt, exec := fakeCreateRuntime(t)
+	opts := fakeCreateOptions(t)
+
+	opts.installed = true
+
+	errFake := errors.New("fake failure")
+
+	exec.Failures["^git commit"] = errFake
+	exec.Outputs["^git commit"] = "Please tell me who you are.\n"
+
+	err := create(context.Background(), opts, rt)
+
+	require.ErrorIs(t, err, errFake)
+	assert.Equal(t, "Commit git repository: fake failure", err.Error())
+	assert.Contains(t, rt.Out.(bufferWriter).String(), "Please tell me who you are.") //nolint:forcetypeassert
+}
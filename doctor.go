@@ -0,0 +1,207 @@
+//nolint:forbidigo
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mgutz/ansi"
+	"github.com/szkiba/create-k6-extension/scaffold"
+)
+
+func doctorCommand(ctx context.Context, rt *runtime, args []string) error {
+	cmd := commands()["doctor"]
+	flags := subcommandFlagset(cmd, rt)
+
+	opts := new(options)
+
+	kindstr := flags.String("type", string(scaffold.JavaScript), "extension type (JavaScript or Output)")
+	flags.BoolVar(&opts.debug, "debug", false, "enable debug output")
+
+	if err := rt.parse(flags, args); err != nil {
+		return err
+	}
+
+	opts.Kind = scaffold.Kind(*kindstr)
+
+	d := &doctor{session: newSession(ctx, opts, rt), ctx: ctx, rt: rt}
+
+	return d.diagnose()
+}
+
+type doctor struct {
+	*session
+	ctx context.Context //nolint:containedctx
+	rt  *runtime
+
+	goVersion string
+	failed    bool
+}
+
+type checkup struct {
+	name     string
+	optional bool
+	fn       func() (string, error)
+}
+
+func (d *doctor) diagnose() error {
+	d.print("\n%s\n", ansi.Color("Checking development environment", "yellow+b"))
+
+	checks := []checkup{
+		{name: "go", fn: d.tool("go", "version")},
+		{name: "git", fn: d.tool("git", "--version")},
+		{name: "xk6", fn: d.tool("xk6", "version"), optional: true},
+		{name: "k6", fn: d.tool("k6", "version"), optional: true},
+		{name: "go version", fn: d.checkGoVersion},
+		{name: "GOPATH/bin on PATH", fn: d.checkGoBin, optional: true},
+		{name: "git user.name", fn: d.gitConfig("user.name")},
+		{name: "git user.email", fn: d.gitConfig("user.email")},
+	}
+
+	for _, check := range checks {
+		d.report(check)
+	}
+
+	if d.failed {
+		return errUnhealthy
+	}
+
+	d.print("\n%s\n", ansi.Color("Your environment is ready for k6 extension development!", "green"))
+
+	return nil
+}
+
+func (d *doctor) report(check checkup) {
+	detail, err := check.fn()
+	if err == nil {
+		d.print("%s %s: %s\n", ansi.Color("✓", "green"), check.name, detail)
+
+		return
+	}
+
+	if check.optional {
+		d.print("%s %s: %s\n", ansi.Color("!", "yellow"), check.name, err)
+
+		return
+	}
+
+	d.failed = true
+
+	d.print("%s %s: %s\n", ansi.Color("✗", "red"), check.name, err)
+}
+
+func (d *doctor) tool(name string, args ...string) func() (string, error) {
+	return func() (string, error) {
+		location, err := d.rt.lookPath(name)
+		if err != nil {
+			return "", fmt.Errorf("%w: %s", errPrerequisite, name)
+		}
+
+		out, err := d.Output("", name, args...)
+		if err != nil {
+			return "", err
+		}
+
+		version, err := scaffold.ParseVersion(string(out))
+		if err != nil {
+			return "", err
+		}
+
+		if name == "go" {
+			d.goVersion = version
+		}
+
+		if required := scaffold.MinVersion(name); len(required) != 0 && scaffold.CompareVersions(version, required) < 0 {
+			return "", fmt.Errorf("%w: %s %s or newer is required, found %s", errOutdated, name, required, version)
+		}
+
+		return fmt.Sprintf("%s (%s)", version, location), nil
+	}
+}
+
+func (d *doctor) checkGoVersion() (string, error) {
+	if len(d.goVersion) == 0 {
+		return "", fmt.Errorf("%w: go", errPrerequisite)
+	}
+
+	dir, err := scaffold.DownloadTemplate(d.ctx, d.rt.executor, d.opts.Kind)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", errTemplateDownload, err.Error())
+	}
+
+	defer os.RemoveAll(dir) //nolint:errcheck
+
+	gomod, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return "", err
+	}
+
+	required, err := scaffold.GoModVersion(gomod)
+	if err != nil {
+		return "", err
+	}
+
+	if scaffold.CompareVersions(d.goVersion, required) < 0 {
+		return "", fmt.Errorf("%w: go %s is required by the %s template, found %s",
+			errOutdated, required, d.opts.Kind, d.goVersion)
+	}
+
+	return fmt.Sprintf("%s template requires go %s", d.opts.Kind, required), nil
+}
+
+func (d *doctor) checkGoBin() (string, error) {
+	out, err := d.Output("", "go", "env", "GOBIN", "GOPATH")
+	if err != nil {
+		return "", err
+	}
+
+	lines := strings.Split(string(out), "\n")
+
+	bin := strings.TrimSpace(lines[0])
+	if len(bin) == 0 && len(lines) > 1 {
+		if gopath := filepath.SplitList(strings.TrimSpace(lines[1])); len(gopath) != 0 {
+			bin = filepath.Join(gopath[0], "bin")
+		}
+	}
+
+	// without HOME and GOPATH (like in minimal containers), go install has nowhere to install the tools
+	if len(bin) == 0 {
+		return "", fmt.Errorf("%w: neither GOBIN nor GOPATH is set, tools installed by go install (like xk6) will fail",
+			errMisconfigured)
+	}
+
+	for _, dir := range filepath.SplitList(d.rt.getenv("PATH")) {
+		if filepath.Clean(dir) == filepath.Clean(bin) {
+			return bin, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %s is not on PATH, tools installed by go install (like xk6) will not be found",
+		errMisconfigured, bin)
+}
+
+func (d *doctor) gitConfig(key string) func() (string, error) {
+	return func() (string, error) {
+		out, err := d.Output("", "git", "config", "--get", key)
+		value := strings.TrimSpace(string(out))
+
+		if err != nil || len(value) == 0 {
+			return "", fmt.Errorf("%w: %s is required for the initial commit, use git config --global %s",
+				errMisconfigured, key, key)
+		}
+
+		return value, nil
+	}
+}
+
+var (
+	errUnhealthy     = errors.New("the development environment has problems")
+	errOutdated      = errors.New("outdated")
+	errMisconfigured = errors.New("misconfigured")
+
+	errTemplateDownload = errors.New("unable to download template")
+)
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/szkiba/create-k6-extension/scaffold"
+	"github.com/szkiba/create-k6-extension/scaffold/scaffoldtest"
+)
+
+// testDoctor returns a doctor using the fake executor of testRuntime.
+// The template downloaded by the fake executor requires go 1.21.
+func testDoctor(t *testing.T, versions map[string]string) (*doctor, *scaffoldtest.Executor) {
+	t.Helper()
+
+	rt, _, _ := testRuntime(versions)
+	rt.getenv = func(string) string { return "" }
+
+	exec := rt.executor.(*scaffoldtest.Executor) //nolint:forcetypeassert
+
+	exec.Hooks["^git clone"] = func(_ string, args []string) error {
+		gomod := "module " + fakeTemplateModule + "\n\ngo 1.21\n"
+
+		return os.WriteFile(filepath.Join(args[len(args)-1], "go.mod"), []byte(gomod), 0o600) //nolint:forbidigo
+	}
+
+	opts := &options{}
+	opts.Kind = scaffold.JavaScript
+
+	ctx := context.Background()
+
+	return &doctor{session: newSession(ctx, opts, rt), ctx: ctx, rt: rt}, exec
+}
+
+func Test_doctor_tool(t *testing.T) {
+	t.Parallel()
+
+	d, exec := testDoctor(t, map[string]string{
+		"go":  "go version go1.21.5 linux/amd64",
+		"git": "git version 2.39.2",
+		"xk6": "no version here",
+	})
+
+	detail, err := d.tool("go", "version")()
+
+	require.NoError(t, err)
+	assert.Equal(t, "1.21.5 (/usr/bin/go)", detail)
+	assert.Equal(t, "1.21.5", d.goVersion)
+	assert.Contains(t, exec.Lines(), "go version")
+
+	detail, err = d.tool("git", "--version")()
+
+	require.NoError(t, err)
+	assert.Equal(t, "2.39.2 (/usr/bin/git)", detail)
+
+	_, err = d.tool("k6", "version")()
+
+	require.ErrorIs(t, err, errPrerequisite)
+	assert.Equal(t, "missing prerequisite: k6", err.Error())
+
+	_, err = d.tool("xk6", "version")()
+
+	assert.Error(t, err)
+}
+
+func Test_doctor_tool_outdated(t *testing.T) {
+	t.Parallel()
+
+	d, _ := testDoctor(t, map[string]string{
+		"go":  "go version go1.21.5 linux/amd64",
+		"git": "git version 2.20.1",
+	})
+
+	_, err := d.tool("git", "--version")()
+
+	require.ErrorIs(t, err, errOutdated)
+	assert.Equal(t, "outdated: git 2.28 or newer is required, found 2.20.1", err.Error())
+}
+
+func Test_doctor_checkGoVersion(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		goVersion string
+		detail    string
+		wantErr   error
+		message   string
+	}{
+		{goVersion: "1.21.5", detail: "JavaScript template requires go 1.21"},
+		{goVersion: "1.21", detail: "JavaScript template requires go 1.21"},
+		{
+			goVersion: "1.20.14",
+			wantErr:   errOutdated,
+			message:   "outdated: go 1.21 is required by the JavaScript template, found 1.20.14",
+		},
+		{goVersion: "", wantErr: errPrerequisite, message: "missing prerequisite: go"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.goVersion, func(t *testing.T) {
+			t.Parallel()
+
+			d, _ := testDoctor(t, nil)
+			d.goVersion = tt.goVersion
+
+			detail, err := d.checkGoVersion()
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Equal(t, tt.message, err.Error())
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.detail, detail)
+		})
+	}
+}
+
+func Test_doctor_checkGoVersion_download(t *testing.T) {
+	t.Parallel()
+
+	d, exec := testDoctor(t, nil)
+	d.goVersion = "1.21.5"
+
+	exec.Failures["^git clone"] = errors.New("exit status 128")
+
+	_, err := d.checkGoVersion()
+
+	require.ErrorIs(t, err, errTemplateDownload)
+}
+
+func Test_doctor_checkGoBin(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		env     string
+		path    string
+		detail  string
+		message string
+	}{
+		{
+			name:   "gopath",
+			env:    "\n/home/gopher/go\n",
+			path:   "/usr/bin:/home/gopher/go/bin",
+			detail: "/home/gopher/go/bin",
+		},
+		{
+			name:   "gobin",
+			env:    "/opt/go/bin\n/home/gopher/go\n",
+			path:   "/opt/go/bin/:/usr/bin",
+			detail: "/opt/go/bin",
+		},
+		{
+			name: "missing",
+			env:  "\n/home/gopher/go\n",
+			path: "/usr/bin",
+			message: "misconfigured: /home/gopher/go/bin is not on PATH, " +
+				"tools installed by go install (like xk6) will not be found",
+		},
+		{
+			name: "unset",
+			env:  "\n\n",
+			path: "/usr/bin",
+			message: "misconfigured: neither GOBIN nor GOPATH is set, " +
+				"tools installed by go install (like xk6) will fail",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			d, exec := testDoctor(t, nil)
+
+			exec.Outputs["^go env GOBIN GOPATH$"] = tt.env
+
+			d.rt.getenv = func(key string) string {
+				if key == "PATH" {
+					return tt.path
+				}
+
+				return ""
+			}
+
+			detail, err := d.checkGoBin()
+
+			if len(tt.message) != 0 {
+				require.ErrorIs(t, err, errMisconfigured)
+				assert.Equal(t, tt.message, err.Error())
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.detail, detail)
+		})
+	}
+}
+
+func Test_doctor_gitConfig(t *testing.T) {
+	t.Parallel()
+
+	d, exec := testDoctor(t, nil)
+
+	exec.Outputs["^git config --get user.name$"] = "Jane Doe\n"
+	exec.Outputs["^git config --get user.email$"] = "\n"
+	exec.Failures["^git config --get user.signingkey$"] = errors.New("exit status 1")
+
+	detail, err := d.gitConfig("user.name")()
+
+	require.NoError(t, err)
+	assert.Equal(t, "Jane Doe", detail)
+
+	for _, key := range []string{"user.email", "user.signingkey"} {
+		_, err = d.gitConfig(key)()
+
+		require.ErrorIs(t, err, errMisconfigured)
+		assert.Equal(t,
+			"misconfigured: "+key+" is required for the initial commit, use git config --global "+key,
+			err.Error(),
+		)
+	}
+}
+
+func Test_doctor_diagnose(t *testing.T) {
+	t.Parallel()
+
+	d, exec := testDoctor(t, map[string]string{
+		"go":  "go version go1.21.5 linux/amd64",
+		"git": "git version 2.39.2",
+	})
+
+	exec.Outputs["^go env GOBIN GOPATH$"] = "/opt/go/bin\n"
+	exec.Outputs["^git config --get user.name$"] = "Jane Doe\n"
+	exec.Outputs["^git config --get user.email$"] = ""
+
+	err := d.diagnose()
+
+	require.ErrorIs(t, err, errUnhealthy)
+
+	// the ansi escape sequences are removed, the colors depend on the other tests
+	out := regexp.MustCompile("\x1b\\[[0-9;]*m").ReplaceAllString(d.Out.(bufferWriter).String(), "") //nolint:forcetypeassert
+
+	assert.Contains(t, out, "✓ go: 1.21.5 (/usr/bin/go)")
+	assert.Contains(t, out, "! xk6: missing prerequisite: xk6")
+	assert.Contains(t, out, "✓ go version: JavaScript template requires go 1.21")
+	assert.Contains(t, out, "! GOPATH/bin on PATH: misconfigured: /opt/go/bin is not on PATH")
+	assert.Contains(t, out, "✓ git user.name: Jane Doe")
+	assert.Contains(t, out, "✗ git user.email: misconfigured: user.email is required")
+	assert.NotContains(t, out, "Your environment is ready")
+
+	exec.Outputs["^git config --get user.email$"] = "jane@example.com\n"
+
+	d.failed = false
+
+	require.NoError(t, d.diagnose())
+}
@@ -1,20 +1,27 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
+	"strings"
+	"time"
 
 	"github.com/spf13/pflag"
+	"github.com/szkiba/create-k6-extension/scaffold"
 	"golang.org/x/term"
 )
 
 func usage(out io.Writer, flags *pflag.FlagSet) {
 	fmt.Fprintf(out,
-		"Usage: %s [flags] [directory]\n\nFlags:\n%s",
+		"Usage: %s [flags] [directory]\n       %s <command> [flags] [args]\n\nFlags:\n%s",
+		_appname,
 		_appname,
 		flags.FlagUsages(),
 	)
+
+	commandUsage(out)
 }
 
 func flagset(opts *options, terminal bool) *pflag.FlagSet {
@@ -23,13 +30,17 @@ func flagset(opts *options, terminal bool) *pflag.FlagSet {
 	flags.BoolVar(&opts.NoAsk, "no-ask", !terminal, "disable interactive questions")
 	flags.StringVar(&opts.Name, "name", "", "extension name")
 	flags.StringVar(&opts.Summary, "summary", "", "a brief summary of the extension")
+	flags.StringSliceVar(&opts.Categories, "category", nil,
+		"comma separated k6 extension registry categories (default: misc)")
 	flags.StringVar(&opts.GoModule, "go-module", "", "go module path")
 	flags.StringVar(&opts.GoPackage, "go-package", "", "go package name (default: extension name)")
 
 	flags.StringVar(&opts.GitOrigin, "git-origin", "", "git origin URL")
 
-	flags.StringVar(&opts.RepoOwner, "repo-owner", "", "GitHub repository owner")
-	flags.StringVar(&opts.RepoName, "repo-name", "", "GitHub repository name")
+	flags.StringVar(&opts.RepoHost, "repo-host", "",
+		"repository hosting service, like gitlab.com or gitlab:git.example.com (default: github.com)")
+	flags.StringVar(&opts.RepoOwner, "repo-owner", "", "repository owner (user, organization or group path, like org/team)")
+	flags.StringVar(&opts.RepoName, "repo-name", "", "repository name")
 	flags.StringVar(
 		&opts.RepoProtocol,
 		"repo-protocol",
@@ -37,27 +48,59 @@ func flagset(opts *options, terminal bool) *pflag.FlagSet {
 		"git repository origin protocol (ssh or https)",
 	)
 
+	flags.BoolVar(&opts.SyncGitHub, "github-sync", false,
+		"create or update the GitHub repository with description and topics (token: GITHUB_TOKEN)")
+	flags.BoolVar(&opts.Publish, "publish", false,
+		"create the GitHub repository and push the initial commit (implies --github-sync)")
+	flags.StringVar(&opts.GitHubAPI, "github-api", "https://api.github.com", "GitHub REST API base URL")
+
+	flags.StringVar(&opts.K6Version, "k6-version", "", "k6 version to use (default: the version pinned by the template)")
+	flags.StringVar(&opts.XK6Version, "xk6-version", "", "xk6 version to install (default: latest)")
+
 	flags.BoolVar(&opts.NoGitInit, "no-git-init", false, "disable git module initialization")
 	flags.BoolVar(&opts.NoGitOrigin, "no-git-origin", false, "disable setting git origin")
 
+	flags.StringVar(&opts.CI, "ci", "",
+		"generate CI workflows for github, gitlab or auto (by repository host) (default: the CI of the template)")
+
+	flags.BoolVar(&opts.Verify, "verify", false, "verify the created extension with go build, go vet and go test")
+	flags.BoolVar(&opts.VerifyK6, "verify-k6", false, "verify that k6 can be built with the extension (implies --verify)")
+	flags.BoolVar(&opts.Smoke, "smoke", false, "run a smoke test with k6 built with the extension (implies --verify-k6)")
+
+	flags.DurationVar(&opts.Timeout, "timeout", 0, "timeout of the commands run by the steps (default: no timeout)")
+	flags.StringVar(&opts.stepTimeouts, "step-timeout", "",
+		"comma separated step=timeout pairs "+
+			"(default: download-template=5m,resolve-k6-version=2m,pin-k6-version=10m,install-xk6=10m,"+
+			"generate-sources=10m,sync-github-repository=1m,publish-repository=5m)")
+	flags.IntVar(&opts.Retries, "retries", 2, "number of retries of the failed network-bound steps")
+
+	flags.StringVar(&opts.catalogLocation, "catalog", "", "known extension catalog file or URL (default: bundled snapshot)")
+	flags.BoolVar(&opts.allowCollision, "allow-name-collision", false, "allow a name already used by a known extension")
+
+	flags.StringVar(&opts.outputFormat, "output", outputText, "output format (text or json, json implies --no-ask)")
 	flags.BoolVar(&opts.debug, "debug", false, "enable debug output")
 
 	return flags
 }
 
-func getopts(rt *runtime) (*options, error) {
+func getopts(ctx context.Context, rt *runtime) (*options, error) {
 	opts := new(options)
 	flags := flagset(opts, term.IsTerminal(int(rt.In.Fd())))
 
-	kindstr := flags.String("type", string(javascript), "extension type (JavaScript or Output)")
+	flags.StringVar(&rt.colorMode, "color", colorAuto, "colored output (auto, always or never)")
+
+	kindstr := flags.String("type", string(scaffold.JavaScript), "extension type (JavaScript or Output)")
 	ver := flags.Bool("version", false, "print version")
 	help := flags.BoolP("help", "h", false, "print this help message")
 
-	if err := flags.Parse(rt.args); err != nil {
+	if err := rt.parse(flags, rt.args); err != nil {
 		return nil, err
 	}
 
-	opts.Kind = kind(*kindstr)
+	opts.Kind = scaffold.Kind(*kindstr)
+	opts.VerifyK6 = opts.VerifyK6 || opts.Smoke
+	opts.Verify = opts.Verify || opts.VerifyK6
+	opts.SyncGitHub = opts.SyncGitHub || opts.Publish
 
 	if *help {
 		usage(rt.Err, flags)
@@ -71,6 +114,25 @@ func getopts(rt *runtime) (*options, error) {
 		return nil, pflag.ErrHelp
 	}
 
+	if opts.outputFormat != outputText && opts.outputFormat != outputJSON {
+		return nil, fmt.Errorf("%w: output: %s", errInvalidFlag, opts.outputFormat)
+	}
+
+	rt.output = opts.outputFormat
+
+	// the questions would be mixed into the JSON stream
+	if opts.outputFormat == outputJSON {
+		if flags.Changed("no-ask") && !opts.NoAsk {
+			return nil, fmt.Errorf("%w: output: %s requires no-ask", errInvalidFlag, opts.outputFormat)
+		}
+
+		opts.NoAsk = true
+	}
+
+	if err := opts.parseStepTimeouts(); err != nil {
+		return nil, err
+	}
+
 	if flags.NArg() > 2 {
 		return nil, errTooManyArg
 	}
@@ -79,7 +141,16 @@ func getopts(rt *runtime) (*options, error) {
 		opts.Dir = flags.Arg(1)
 	}
 
-	opts.update()
+	opts.Update()
+
+	opts.GitHubToken = rt.getenv("GITHUB_TOKEN")
+	if len(opts.GitHubToken) == 0 {
+		opts.GitHubToken = rt.getenv("GH_TOKEN")
+	}
+
+	if err := opts.loadCatalog(ctx); err != nil {
+		return nil, err
+	}
 
 	_, err := rt.lookPath("xk6")
 	opts.installed = err == nil
@@ -88,25 +159,80 @@ func getopts(rt *runtime) (*options, error) {
 		return opts, nil
 	}
 
-	opts.guess()
+	opts.Guess()
 
-	if len(opts.Name) == 0 {
-		return nil, fmt.Errorf("%w: %s", errMissingFlag, "name")
+	if err := opts.Validate(); err != nil {
+		return nil, err
 	}
 
-	if len(opts.GoModule) == 0 {
-		return nil, fmt.Errorf("%w: %s", errMissingFlag, "go-module")
+	if !opts.allowCollision {
+		if err := opts.catalog.Check(opts.Kind, opts.Name); err != nil {
+			return nil, err
+		}
 	}
 
-	if len(opts.Dir) == 0 {
-		return nil, fmt.Errorf("%w: %s", errMissingArg, "directory")
+	return opts, nil
+}
+
+// loadCatalog loads the known extension catalog used to detect name collisions.
+// Without the --catalog flag, the snapshot bundled with the scaffold package is used.
+func (opts *options) loadCatalog(ctx context.Context) error {
+	if len(opts.catalogLocation) == 0 {
+		opts.catalog = scaffold.BundledCatalog()
+
+		return nil
 	}
 
-	return opts, nil
+	var err error
+
+	opts.catalog, err = scaffold.LoadCatalog(ctx, opts.catalogLocation)
+	if err != nil {
+		return fmt.Errorf("%w: catalog: %s", errInvalidFlag, err.Error())
+	}
+
+	return nil
+}
+
+// parseStepTimeouts parses the --step-timeout values over the default timeouts of the network-bound steps,
+// which may hang on flaky proxies.
+func (opts *options) parseStepTimeouts() error {
+	opts.StepTimeouts = map[string]time.Duration{
+		"download-template":      5 * time.Minute,
+		"resolve-k6-version":     2 * time.Minute,
+		"pin-k6-version":         10 * time.Minute,
+		"install-xk6":            10 * time.Minute,
+		"generate-sources":       10 * time.Minute,
+		"sync-github-repository": time.Minute,
+		"publish-repository":     5 * time.Minute,
+	}
+
+	if len(opts.stepTimeouts) == 0 {
+		return nil
+	}
+
+	for _, pair := range strings.Split(opts.stepTimeouts, ",") {
+		step, value, found := strings.Cut(pair, "=")
+
+		timeout, err := time.ParseDuration(value)
+		if !found || err != nil {
+			return fmt.Errorf("%w: step-timeout: %s", errInvalidFlag, pair)
+		}
+
+		if err := scaffold.CheckStep(step); err != nil {
+			return fmt.Errorf("%w: step-timeout: %s", errInvalidFlag, err.Error())
+		}
+
+		// the keys are normalized, so the given timeout replaces the default one whatever form is used
+		opts.StepTimeouts[scaffold.StepKey(step)] = timeout
+	}
+
+	return nil
 }
 
 var (
 	errMissingFlag = errors.New("missing required flag")
 	errTooManyArg  = errors.New("too many arguments")
 	errMissingArg  = errors.New("missing argument")
+	errInvalidArg  = errors.New("invalid argument")
+	errInvalidFlag = errors.New("invalid flag value")
 )
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/szkiba/create-k6-extension/scaffold"
+)
+
+func Test_options_parseStepTimeouts(t *testing.T) {
+	t.Parallel()
+
+	opts := &options{stepTimeouts: "Install xk6=30m, verify-go-test=1m"}
+
+	require.NoError(t, opts.parseStepTimeouts())
+
+	assert.Equal(t, map[string]time.Duration{
+		"download-template":      5 * time.Minute,
+		"resolve-k6-version":     2 * time.Minute,
+		"pin-k6-version":         10 * time.Minute,
+		"install-xk6":            30 * time.Minute,
+		"generate-sources":       10 * time.Minute,
+		"verify-go-test":         time.Minute,
+		"sync-github-repository": time.Minute,
+		"publish-repository":     5 * time.Minute,
+	}, opts.StepTimeouts)
+
+	for _, value := range []string{"install-xk6", "install-xk6=forever", "download-templat=1m"} {
+		opts := &options{stepTimeouts: value}
+
+		assert.ErrorIs(t, opts.parseStepTimeouts(), errInvalidFlag)
+	}
+}
+
+type bufferReader struct {
+	*strings.Reader
+}
+
+// Fd returns an invalid file descriptor, so the reader is never considered a terminal.
+func (bufferReader) Fd() uintptr {
+	return ^uintptr(0)
+}
+
+func Test_getopts_invalid(t *testing.T) {
+	t.Parallel()
+
+	rt, stderr, code := testRuntime(nil)
+
+	rt.In = bufferReader{strings.NewReader("")}
+	rt.getenv = func(string) string { return "" }
+	rt.args = []string{_appname, "--name", "Foo", "--go-package", "func", "--repo-owner", "acme", "--repo-protocol", "ftp"}
+
+	_, err := getopts(context.Background(), rt)
+
+	require.ErrorIs(t, err, scaffold.ErrInvalidOption)
+
+	rt.fail(err)
+
+	assert.Equal(t, 1, *code)
+	assert.Equal(t, `error: invalid name "Foo": minimum 3 maximum 32 lower case alphanumeric characters are required
+error: invalid goPackage "func": valid go identifier (not a keyword) is required
+error: invalid repoProtocol "ftp": ssh or https is required
+`, stderr.String())
+}
+
+func Test_getopts_name_collision(t *testing.T) {
+	t.Parallel()
+
+	rt, _, _ := testRuntime(nil)
+
+	rt.In = bufferReader{strings.NewReader("")}
+	rt.getenv = func(string) string { return "" }
+	rt.args = []string{_appname, "--no-ask", "--name", "sql", "--repo-owner", "acme"}
+
+	_, err := getopts(context.Background(), rt)
+
+	require.ErrorIs(t, err, scaffold.ErrNameCollision)
+	assert.Contains(t, err.Error(), "github.com/grafana/xk6-sql, try sqlx, mysql, sqlext")
+
+	rt.args = append(rt.args, "--allow-name-collision")
+
+	opts, err := getopts(context.Background(), rt)
+
+	require.NoError(t, err)
+	assert.Equal(t, "sql", opts.Name)
+}
+
+func Test_getopts_github_sync(t *testing.T) {
+	t.Parallel()
+
+	rt, _, _ := testRuntime(nil)
+
+	env := map[string]string{"GH_TOKEN": "secret"}
+
+	rt.In = bufferReader{strings.NewReader("")}
+	rt.getenv = func(name string) string { return env[name] }
+	rt.args = []string{_appname, "--no-ask", "--name", "foo", "--repo-owner", "acme", "--github-sync"}
+
+	opts, err := getopts(context.Background(), rt)
+
+	require.NoError(t, err)
+	assert.True(t, opts.SyncGitHub)
+	assert.Equal(t, "secret", opts.GitHubToken)
+	assert.Equal(t, "https://api.github.com", opts.GitHubAPI)
+
+	env = nil
+
+	_, err = getopts(context.Background(), rt)
+
+	require.ErrorIs(t, err, scaffold.ErrInvalidOption)
+	assert.Contains(t, err.Error(), "gitHubToken")
+}
+
+func Test_getopts_ci(t *testing.T) {
+	t.Parallel()
+
+	rt, _, _ := testRuntime(nil)
+
+	rt.In = bufferReader{strings.NewReader("")}
+	rt.getenv = func(string) string { return "" }
+	rt.args = []string{_appname, "--no-ask", "--name", "foo", "--repo-owner", "acme", "--repo-host", "gitlab.com", "--ci", "auto"}
+
+	opts, err := getopts(context.Background(), rt)
+
+	require.NoError(t, err)
+	assert.Equal(t, scaffold.CIGitLab, opts.CIPlatform())
+
+	rt.args = []string{_appname, "--no-ask", "--name", "foo", "--ci", "jenkins"}
+
+	_, err = getopts(context.Background(), rt)
+
+	require.ErrorIs(t, err, scaffold.ErrInvalidOption)
+	assert.Contains(t, err.Error(), `ci "jenkins"`)
+}
+
+func Test_getopts_json(t *testing.T) {
+	t.Parallel()
+
+	rt, _, _ := testRuntime(nil)
+
+	rt.In = bufferReader{strings.NewReader("")}
+	rt.getenv = func(string) string { return "" }
+	rt.args = []string{_appname, "--output", "json", "--name", "foo", "--repo-owner", "acme", "--repo-protocol", "ssh"}
+
+	opts, err := getopts(context.Background(), rt)
+
+	require.NoError(t, err)
+	assert.True(t, opts.NoAsk)
+
+	rt.args = []string{_appname, "--output", "json", "--no-ask=false", "--name", "foo"}
+
+	_, err = getopts(context.Background(), rt)
+
+	require.ErrorIs(t, err, errInvalidFlag)
+}
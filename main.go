@@ -2,9 +2,14 @@
 package main
 
 import (
+	"context"
 	"errors"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/spf13/pflag"
+	"github.com/szkiba/create-k6-extension/scaffold"
 )
 
 var (
@@ -15,37 +20,50 @@ var (
 func main() {
 	rt := stdRuntime()
 
-	rt.prerequisite()
+	rt.presetColor()
 
-	run(rt)
-}
+	// the running step is canceled on interrupt, the questions handle ctrl-c themselves
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-func run(rt *runtime) {
-	var err error
-	var opts *options
+	if cmd, found := lookupCommand(rt.args); found {
+		runCommand(ctx, rt, cmd)
 
-	opts, err = getopts(rt)
-	if errors.Is(err, pflag.ErrHelp) {
 		return
 	}
 
-	if err != nil {
+	if err := run(ctx, rt); err != nil && !errors.Is(err, pflag.ErrHelp) {
 		rt.fail(err)
 	}
+}
 
-	var confirm bool
-
-	confirm, err = ask(opts, rt.Stdio)
+// run runs the interactive (or non-interactive) creation.
+// The error is returned instead of exiting, so the deferred cleanup always runs.
+func run(ctx context.Context, rt *runtime) error {
+	opts, err := getopts(ctx, rt)
 	if err != nil {
-		rt.fail(err)
+		return err
 	}
 
-	if !confirm {
-		return
-	}
+	rt.prerequisite()
 
-	err = create(opts, rt.Stdio)
-	if err != nil {
-		rt.fail(err)
+	defer rt.templates.Close()
+
+	confirm, err := ask(opts, rt)
+	if err != nil || !confirm {
+		return err
 	}
+
+	return create(ctx, opts, rt)
+}
+
+func create(ctx context.Context, opts *options, rt *runtime) error {
+	opts.NoInstall = opts.NoInstall || opts.installed
+	opts.Executor = rt.executor
+	opts.Templates = rt.templates
+	opts.OnEvent = notify(newReporter(opts, rt))
+
+	_, err := scaffold.Create(ctx, &opts.Options)
+
+	return err
 }
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/mgutz/ansi"
+	"github.com/szkiba/create-k6-extension/scaffold"
+)
+
+func registryEntryCommand(ctx context.Context, rt *runtime, args []string) error {
+	cmd := commands()["registry-entry"]
+	flags := subcommandFlagset(cmd, rt)
+
+	opts := new(options)
+
+	flags.StringVar(&opts.Dir, "dir", ".", "extension directory")
+	flags.StringVar(&opts.Summary, "summary", "", "a brief summary of the extension (default: the current description)")
+	flags.StringSliceVar(&opts.Categories, "category", nil,
+		"comma separated k6 extension registry categories (default: the current categories)")
+	flags.BoolVar(&opts.debug, "debug", false, "enable debug output")
+
+	if err := rt.parse(flags, args); err != nil {
+		return err
+	}
+
+	rt.prerequisite()
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+
+		return errTooManyArg
+	}
+
+	abs, err := filepath.Abs(opts.Dir)
+	if err != nil {
+		return err
+	}
+
+	opts.Dir = abs
+
+	s := newSession(ctx, opts, rt)
+
+	bin, err := s.Output(abs, "go", "list", "-m")
+	if err != nil {
+		return err
+	}
+
+	current, err := scaffold.ReadRegistryEntry(abs)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	entry := registryEntry(strings.TrimSpace(string(bin)), current, &opts.Options)
+
+	if err := scaffold.CheckCategories(entry.Categories); err != nil {
+		return fmt.Errorf("%w: category: %s", errInvalidFlag, err.Error())
+	}
+
+	s.print("\n%s\n", ansi.Color("Generating registry entry", "yellow+b"))
+
+	err = s.Step("Write registry entry", func() error {
+		return scaffold.WriteRegistryEntry(abs, entry)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.print("\n%s\n  %s\n", ansi.Color("The registry entry has been written to:", "green"),
+		ansi.Color(scaffold.RegistryEntryFile, "yellow"))
+
+	return nil
+}
+
+// registryEntry returns the registry entry of an existing extension with the given go module path.
+// The description, the categories and the tier of the current entry are kept, unless given by the flags.
+func registryEntry(module string, current *scaffold.RegistryEntry, flags *scaffold.Options) *scaffold.RegistryEntry {
+	opts := moduleOptions(module)
+
+	opts.GoModule = module
+	opts.Name = strings.TrimPrefix(path.Base(module), opts.Kind.RepoNamePrefix())
+	opts.Summary = flags.Summary
+	opts.Categories = flags.Categories
+
+	if current != nil {
+		if len(opts.Summary) == 0 {
+			opts.Summary = current.Description
+		}
+
+		if len(opts.Categories) == 0 {
+			opts.Categories = current.Categories
+		}
+	}
+
+	entry := opts.RegistryEntry()
+
+	if current != nil && len(current.Tier) != 0 {
+		entry.Tier = current.Tier
+	}
+
+	return entry
+}
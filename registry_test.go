@@ -0,0 +1,38 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/szkiba/create-k6-extension/scaffold"
+)
+
+func Test_registryEntry(t *testing.T) {
+	t.Parallel()
+
+	entry := registryEntry("github.com/acme/xk6-output-bar", nil, new(scaffold.Options))
+
+	assert.Equal(t, &scaffold.RegistryEntry{
+		Module:     "github.com/acme/xk6-output-bar",
+		Outputs:    []string{"bar"},
+		Tier:       scaffold.TierCommunity,
+		Categories: []string{"misc"},
+	}, entry)
+
+	current := &scaffold.RegistryEntry{
+		Module:      "github.com/acme/xk6-foo",
+		Description: "Foo extension",
+		Tier:        "partner",
+		Categories:  []string{"data"},
+	}
+
+	entry = registryEntry("example.com/team/xk6-foo", current, &scaffold.Options{Categories: []string{"protocol"}})
+
+	assert.Equal(t, &scaffold.RegistryEntry{
+		Module:      "example.com/team/xk6-foo",
+		Description: "Foo extension",
+		Imports:     []string{"k6/x/foo"},
+		Tier:        "partner",
+		Categories:  []string{"protocol"},
+	}, entry)
+}
@@ -0,0 +1,465 @@
+//nolint:forbidigo
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/mgutz/ansi"
+	"github.com/szkiba/create-k6-extension/scaffold"
+	"golang.org/x/term"
+)
+
+func renameCommand(ctx context.Context, rt *runtime, args []string) error {
+	cmd := commands()["rename"]
+	flags := subcommandFlagset(cmd, rt)
+
+	var dir string
+	var noAsk, dryRun, debug bool
+
+	flags.StringVar(&dir, "dir", ".", "extension directory")
+	flags.BoolVar(&noAsk, "no-ask", !term.IsTerminal(int(rt.In.Fd())), "do not ask for confirmation")
+	flags.BoolVar(&dryRun, "dry-run", false, "only print the changes")
+	flags.BoolVar(&debug, "debug", false, "enable debug output")
+
+	if err := rt.parse(flags, args); err != nil {
+		return err
+	}
+
+	rt.prerequisite()
+
+	if flags.NArg() != 3 {
+		flags.Usage()
+
+		return fmt.Errorf("%w: expected old and new name", errMissingArg)
+	}
+
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+
+	opts := &options{debug: debug}
+	opts.Dir = abs
+
+	s := newSession(ctx, opts, rt)
+
+	bin, err := s.Output(abs, "go", "list", "-m")
+	if err != nil {
+		return err
+	}
+
+	from, to := renameOptions(strings.TrimSpace(string(bin)), flags.Arg(1), flags.Arg(2))
+
+	if err = checkRenameTarget(to); err != nil {
+		return err
+	}
+
+	r := newRenamer(from, to)
+
+	if err = r.scan(abs); err != nil {
+		return err
+	}
+
+	r.preview(s)
+
+	if dryRun || len(r.changes) == 0 {
+		return nil
+	}
+
+	if !noAsk {
+		var ok bool
+
+		prompt := &survey.Confirm{Message: "Apply the above changes?"}
+
+		if err = survey.AskOne(prompt, &ok, survey.WithStdio(rt.In, rt.Out, rt.Err)); err != nil || !ok {
+			return err
+		}
+	}
+
+	return r.apply(s)
+}
+
+// moduleOptions returns the options guessed from the go module path of an existing extension:
+// the kind, and the repository host and owner of a module on a known hosting service
+// (otherwise the module path itself).
+func moduleOptions(module string) *scaffold.Options {
+	opts := &scaffold.Options{Kind: scaffold.JavaScript}
+
+	if strings.HasPrefix(module[strings.LastIndex(module, "/")+1:], scaffold.Output.RepoNamePrefix()) {
+		opts.Kind = scaffold.Output
+	}
+
+	if parts := strings.Split(module, "/"); len(parts) >= 3 && scaffold.CheckRepoHost(parts[0]) == nil {
+		opts.RepoHost = parts[0]
+		opts.RepoOwner = strings.Join(parts[1:len(parts)-1], "/")
+		opts.RepoProtocol = "ssh"
+	} else {
+		opts.GoModule = module
+	}
+
+	return opts
+}
+
+// renameOptions returns the options derived from the old and the new extension name.
+// The kind and the repository owner are guessed from the current go module path.
+func renameOptions(module, oldName, newName string) (*scaffold.Options, *scaffold.Options) {
+	from := moduleOptions(module)
+	from.Name = oldName
+
+	to := &scaffold.Options{
+		Name:         newName,
+		Kind:         from.Kind,
+		RepoHost:     from.RepoHost,
+		RepoOwner:    from.RepoOwner,
+		RepoProtocol: from.RepoProtocol,
+	}
+
+	if len(from.GoModule) != 0 {
+		to.GoModule = strings.TrimSuffix(module, from.Kind.RepoNamePrefix()+oldName) +
+			to.Kind.RepoNamePrefix() + newName
+	}
+
+	for _, opts := range []*scaffold.Options{from, to} {
+		opts.Guess()
+		opts.Update()
+	}
+
+	return from, to
+}
+
+// checkRenameTarget checks the new name and the go package derived from it,
+// so nothing is rewritten with an invalid name.
+func checkRenameTarget(to *scaffold.Options) error {
+	if err := scaffold.CheckName(to.Name); err != nil {
+		return fmt.Errorf("%w: new name: %s: %s", errInvalidArg, to.Name, err.Error())
+	}
+
+	if err := scaffold.CheckGoPackage(to.GoPackage); err != nil {
+		return fmt.Errorf("%w: go package: %s: %s", errInvalidArg, to.GoPackage, err.Error())
+	}
+
+	return nil
+}
+
+type fileChange struct {
+	path    string
+	newPath string
+	before  []byte
+	after   []byte
+}
+
+// replacement replaces a value only if it is not part of a longer token.
+// The left and right functions report the characters that must not surround the value.
+type replacement struct {
+	from, to    string
+	left, right func(byte) bool
+}
+
+type renamer struct {
+	replacements     []*replacement
+	changes          []*fileChange
+	fromRepo, toRepo string
+}
+
+func isIdentChar(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
+
+func isAlnum(c byte) bool {
+	return c != '_' && isIdentChar(c)
+}
+
+// isNameChar reports the characters of the names (including dash, so go-sql-driver does not contain sql).
+func isNameChar(c byte) bool {
+	return c == '-' || isIdentChar(c)
+}
+
+func isPathChar(c byte) bool {
+	return c == '.' || c == '/' || isNameChar(c)
+}
+
+func isDomainChar(c byte) bool {
+	return c == '.' || isNameChar(c)
+}
+
+// newRenamer returns a renamer, which replaces only the exact derived values
+// (go module path, JavaScript module name, repository name, environment variable prefix)
+// and the name, the go package name and the primary class name as whole identifiers.
+// The repository owner is never replaced.
+func newRenamer(from, to *scaffold.Options) *renamer {
+	r := &renamer{fromRepo: from.RepoName, toRepo: to.RepoName}
+
+	add := func(value, with string, left, right func(byte) bool) {
+		if len(value) != 0 {
+			r.replacements = append(r.replacements, &replacement{from: value, to: with, left: left, right: right})
+		}
+	}
+
+	if len(from.RepoOwner) != 0 {
+		domain := from.Host().Domain
+
+		// kept as is, so an owner equal to the name is not replaced
+		for _, sep := range []string{"/", ":"} {
+			add(domain+sep+from.RepoOwner, domain+sep+from.RepoOwner, isDomainChar, isNameChar)
+		}
+	}
+
+	add(from.GoModule, to.GoModule, isPathChar, isDomainChar)
+	add("k6/x/"+from.Name, "k6/x/"+to.Name, isPathChar, isNameChar)
+	add(from.RepoName, to.RepoName, isNameChar, isNameChar)
+	add(from.EnvPrefix, to.EnvPrefix, isIdentChar, isAlnum)
+	add(from.PrimaryClass, to.PrimaryClass, isNameChar, isNameChar)
+	add(from.GoPackage, to.GoPackage, isNameChar, isNameChar)
+	add(from.Name, to.Name, isNameChar, isNameChar)
+
+	// longer values first, so the module path wins over the name it contains
+	sort.SliceStable(r.replacements, func(i, j int) bool {
+		return len(r.replacements[i].from) > len(r.replacements[j].from)
+	})
+
+	return r
+}
+
+func (r *renamer) match(str string, idx int) *replacement {
+	for _, rep := range r.replacements {
+		if !strings.HasPrefix(str[idx:], rep.from) {
+			continue
+		}
+
+		if idx > 0 && rep.left(str[idx-1]) {
+			continue
+		}
+
+		if end := idx + len(rep.from); end < len(str) && rep.right(str[end]) {
+			continue
+		}
+
+		return rep
+	}
+
+	return nil
+}
+
+func (r *renamer) replace(str string) string {
+	var buff strings.Builder
+
+	for idx := 0; idx < len(str); {
+		if rep := r.match(str, idx); rep != nil {
+			buff.WriteString(rep.to)
+			idx += len(rep.from)
+
+			continue
+		}
+
+		buff.WriteByte(str[idx])
+		idx++
+	}
+
+	return buff.String()
+}
+
+// replaceGoMod replaces only the module directive, the dependencies are not renamed.
+func (r *renamer) replaceGoMod(content string) string {
+	lines := strings.SplitAfter(content, "\n")
+
+	for idx, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "module ") {
+			lines[idx] = r.replace(line)
+		}
+	}
+
+	return strings.Join(lines, "")
+}
+
+func (r *renamer) scan(dir string) error {
+	existing := make(map[string]bool)
+
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			if entry.Name() == ".git" {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		existing[path] = true
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil || rel == "go.sum" {
+			return err
+		}
+
+		before, err := os.ReadFile(filepath.Clean(path))
+		if err != nil {
+			return err
+		}
+
+		change := &fileChange{
+			path:    path,
+			newPath: filepath.Join(dir, r.replace(rel)),
+			before:  before,
+			after:   before,
+		}
+
+		switch {
+		case rel == "go.mod":
+			change.after = []byte(r.replaceGoMod(string(before)))
+		case bytes.IndexByte(before, 0) < 0:
+			change.after = []byte(r.replace(string(before)))
+		}
+
+		if change.path != change.newPath || !bytes.Equal(change.before, change.after) {
+			r.changes = append(r.changes, change)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return r.conflicts(existing)
+}
+
+func (r *renamer) conflicts(existing map[string]bool) error {
+	targets := make(map[string]bool, len(r.changes))
+
+	for _, change := range r.changes {
+		if change.path == change.newPath {
+			continue
+		}
+
+		if existing[change.newPath] || targets[change.newPath] {
+			return fmt.Errorf("%w: %s", errRenameConflict, change.newPath)
+		}
+
+		targets[change.newPath] = true
+	}
+
+	return nil
+}
+
+func (r *renamer) preview(s *session) {
+	if len(r.changes) == 0 {
+		s.print("%s\n", ansi.Color("Nothing to rename.", "yellow"))
+
+		return
+	}
+
+	for _, change := range r.changes {
+		s.print("%s\n%s\n", ansi.Color("--- "+change.path, "red+b"), ansi.Color("+++ "+change.newPath, "green+b"))
+
+		before := strings.Split(string(change.before), "\n")
+		after := strings.Split(string(change.after), "\n")
+
+		if len(before) != len(after) {
+			continue
+		}
+
+		for idx := range before {
+			if before[idx] == after[idx] {
+				continue
+			}
+
+			s.print("%s\n%s\n",
+				ansi.Color(fmt.Sprintf("@@ %d @@ -%s", idx+1, before[idx]), "red"),
+				ansi.Color(fmt.Sprintf("@@ %d @@ +%s", idx+1, after[idx]), "green"),
+			)
+		}
+	}
+}
+
+func (r *renamer) apply(s *session) error {
+	s.print("\n%s\n", ansi.Color("Renaming extension", "yellow+b"))
+
+	err := s.Step("Rewrite files", func() error {
+		var moved []string
+
+		for _, change := range r.changes {
+			if err := os.WriteFile(change.path, change.after, 0o600); err != nil {
+				return err
+			}
+
+			if change.path == change.newPath {
+				continue
+			}
+
+			if err := os.MkdirAll(filepath.Dir(change.newPath), 0o750); err != nil {
+				return err
+			}
+
+			if err := os.Rename(change.path, change.newPath); err != nil {
+				return err
+			}
+
+			moved = append(moved, filepath.Dir(change.path))
+		}
+
+		removeEmptyDirs(s.opts.Dir, moved)
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	origin, err := s.Output(s.opts.Dir, "git", "remote", "get-url", "origin")
+	if err != nil {
+		return nil //nolint:nilerr
+	}
+
+	from := strings.TrimSpace(string(origin))
+	to := renameOrigin(from, r.fromRepo, r.toRepo)
+
+	if from == to {
+		return nil
+	}
+
+	return s.Step("Update git origin", func() error {
+		return s.Run(s.opts.Dir, "git", "remote", "set-url", "origin", to)
+	})
+}
+
+// removeEmptyDirs removes the directories (and their parents up to root) left empty
+// by moving the files out of them, like the directory of a renamed subpackage.
+func removeEmptyDirs(root string, dirs []string) {
+	sort.Slice(dirs, func(i, j int) bool { return len(dirs[i]) > len(dirs[j]) })
+
+	for _, dir := range dirs {
+		for ; dir != root && strings.HasPrefix(dir, root+string(filepath.Separator)); dir = filepath.Dir(dir) {
+			// only empty directories can be removed
+			if os.Remove(dir) != nil {
+				break
+			}
+		}
+	}
+}
+
+// renameOrigin replaces the repository name (the last path element) of the git origin URL.
+// The host and the owner are kept as is.
+func renameOrigin(origin, oldRepo, newRepo string) string {
+	base := strings.TrimSuffix(origin, ".git")
+	idx := strings.LastIndexAny(base, "/:")
+
+	if len(oldRepo) == 0 || idx < 0 || base[idx+1:] != oldRepo {
+		return origin
+	}
+
+	return base[:idx+1] + newRepo + origin[len(base):]
+}
+
+var errRenameConflict = errors.New("rename target already exists")
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/szkiba/create-k6-extension/scaffold"
+	"github.com/szkiba/create-k6-extension/scaffold/scaffoldtest"
+)
+
+func Test_renameOptions(t *testing.T) {
+	t.Parallel()
+
+	from, to := renameOptions("github.com/acme/xk6-output-foo", "foo", "fancy_bar")
+
+	assert.Equal(t, scaffold.Output, from.Kind)
+	assert.Equal(t, "github.com/acme/xk6-output-foo", from.GoModule)
+	assert.Equal(t, "github.com/acme/xk6-output-fancy_bar", to.GoModule)
+	assert.Equal(t, "xk6-output-fancy_bar", to.RepoName)
+	assert.Equal(t, "FancyBar", to.PrimaryClass)
+	assert.Equal(t, "XK6_OUTPUT_FANCY_BAR", to.EnvPrefix)
+}
+
+func Test_renameOptions_customModule(t *testing.T) {
+	t.Parallel()
+
+	from, to := renameOptions("example.com/team/xk6-foo", "foo", "bar")
+
+	assert.Equal(t, scaffold.JavaScript, from.Kind)
+	assert.Equal(t, "example.com/team/xk6-bar", to.GoModule)
+}
+
+func Test_checkRenameTarget(t *testing.T) {
+	t.Parallel()
+
+	_, to := renameOptions("github.com/acme/xk6-foo", "foo", "bar")
+
+	require.NoError(t, checkRenameTarget(to))
+
+	for _, name := range []string{"Foo-Bar!", "ba", "func"} {
+		_, to := renameOptions("github.com/acme/xk6-foo", "foo", name)
+
+		assert.ErrorIs(t, checkRenameTarget(to), errInvalidArg, name)
+	}
+}
+
+func Test_renamer_replace(t *testing.T) {
+	t.Parallel()
+
+	r := newRenamer(renameOptions("github.com/acme/xk6-foo", "foo", "bar"))
+
+	assert.Equal(t,
+		`import bar from "k6/x/bar"; new Bar(__ENV.XK6_BAR_URL) // github.com/acme/xk6-bar`,
+		r.replace(`import foo from "k6/x/foo"; new Foo(__ENV.XK6_FOO_URL) // github.com/acme/xk6-foo`),
+	)
+}
+
+func Test_renamer_replace_wholeIdentifiers(t *testing.T) {
+	t.Parallel()
+
+	r := newRenamer(renameOptions("github.com/sqlteam/xk6-sql", "sql", "db"))
+
+	for input, expected := range map[string]string{
+		`import sql from "k6/x/sql"`:                   `import db from "k6/x/db"`,
+		`package sql`:                                  `package db`,
+		`github.com/sqlteam/xk6-sql/internal`:          `github.com/sqlteam/xk6-db/internal`,
+		`git clone git@github.com:sqlteam/xk6-sql.git`: `git clone git@github.com:sqlteam/xk6-db.git`,
+		`XK6_SQL_URL`:                                  `XK6_DB_URL`,
+		`github.com/go-sql-driver/mysql`:               `github.com/go-sql-driver/mysql`,
+		`sqlite and mssql and sql_test`:                `sqlite and mssql and sql_test`,
+		`xk6-sql-driver-mysql`:                         `xk6-sql-driver-mysql`,
+		`github.com/sqlteam/sqlstuff`:                  `github.com/sqlteam/sqlstuff`,
+	} {
+		assert.Equal(t, expected, r.replace(input), input)
+	}
+}
+
+func Test_renamer_replace_ownerEqualsName(t *testing.T) {
+	t.Parallel()
+
+	r := newRenamer(renameOptions("github.com/sql/xk6-sql", "sql", "db"))
+
+	assert.Equal(t, "https://github.com/sql/xk6-db", r.replace("https://github.com/sql/xk6-sql"))
+	assert.Equal(t, "git@github.com:sql/xk6-db.git", r.replace("git@github.com:sql/xk6-sql.git"))
+}
+
+func Test_renameOrigin(t *testing.T) {
+	t.Parallel()
+
+	for origin, expected := range map[string]string{
+		"git@github.com:sqlteam/xk6-sql.git":    "git@github.com:sqlteam/xk6-db.git",
+		"https://github.com/sqlteam/xk6-sql":    "https://github.com/sqlteam/xk6-db",
+		"https://gitlab.com/sql/k6/xk6-sql.git": "https://gitlab.com/sql/k6/xk6-db.git",
+		"git@github.com:sqlteam/xk6-sqlite.git": "git@github.com:sqlteam/xk6-sqlite.git",
+		"git@github.com:xk6-sql/something.git":  "git@github.com:xk6-sql/something.git",
+	} {
+		assert.Equal(t, expected, renameOrigin(origin, "xk6-sql", "xk6-db"), origin)
+	}
+}
+
+// renameFixture writes a small xk6-sql extension with a subpackage into a temporary directory.
+func renameFixture(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"go.mod": "module github.com/sqlteam/xk6-sql\n\ngo 1.21\n\nrequire (\n" +
+			"\tgithub.com/go-sql-driver/mysql v1.7.1\n\tgithub.com/sqlteam/xk6-sql-driver v0.1.0\n)\n",
+		"go.sum":     "github.com/go-sql-driver/mysql v1.7.1 h1:xxx=\ngithub.com/sqlteam/xk6-sql-driver v0.1.0 h1:yyy=\n",
+		"sql.go":     "// Package sql contains the xk6-sql extension.\npackage sql\n\nimport _ \"github.com/go-sql-driver/mysql\"\n",
+		"sqlite.go":  "package sql\n\n// sqlite support\n",
+		"index.d.ts": "declare module \"k6/x/sql\" {}\n",
+		"README.md":  "# xk6-sql\n\nSee https://github.com/sqlteam/xk6-sql for sqlite and mysql.\n",
+		"sql/sql.go": "package sql\n",
+	}
+
+	for name, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o750))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+
+	return dir
+}
+
+func Test_renamer_scan(t *testing.T) {
+	t.Parallel()
+
+	dir := renameFixture(t)
+	r := newRenamer(renameOptions("github.com/sqlteam/xk6-sql", "sql", "db"))
+
+	require.NoError(t, r.scan(dir))
+
+	changes := make(map[string]*fileChange, len(r.changes))
+
+	for _, change := range r.changes {
+		rel, err := filepath.Rel(dir, change.path)
+		require.NoError(t, err)
+
+		changes[rel] = change
+	}
+
+	assert.NotContains(t, changes, "go.sum")
+
+	if assert.Contains(t, changes, "sqlite.go") {
+		assert.Equal(t, filepath.Join(dir, "sqlite.go"), changes["sqlite.go"].newPath)
+		assert.Equal(t, "package db\n\n// sqlite support\n", string(changes["sqlite.go"].after))
+	}
+
+	if assert.Contains(t, changes, "go.mod") {
+		after := string(changes["go.mod"].after)
+
+		assert.Contains(t, after, "module github.com/sqlteam/xk6-db\n")
+		assert.Contains(t, after, "github.com/go-sql-driver/mysql v1.7.1")
+		assert.Contains(t, after, "github.com/sqlteam/xk6-sql-driver v0.1.0")
+	}
+
+	if assert.Contains(t, changes, "sql.go") {
+		assert.Equal(t, filepath.Join(dir, "db.go"), changes["sql.go"].newPath)
+		assert.Equal(t,
+			"// Package db contains the xk6-db extension.\npackage db\n\nimport _ \"github.com/go-sql-driver/mysql\"\n",
+			string(changes["sql.go"].after),
+		)
+	}
+
+	if assert.Contains(t, changes, "index.d.ts") {
+		assert.Equal(t, "declare module \"k6/x/db\" {}\n", string(changes["index.d.ts"].after))
+	}
+
+	if assert.Contains(t, changes, "README.md") {
+		assert.Equal(t,
+			"# xk6-db\n\nSee https://github.com/sqlteam/xk6-db for sqlite and mysql.\n",
+			string(changes["README.md"].after),
+		)
+	}
+}
+
+func Test_renamer_scan_conflict(t *testing.T) {
+	t.Parallel()
+
+	dir := renameFixture(t)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "db.go"), []byte("package sql\n"), 0o600))
+
+	r := newRenamer(renameOptions("github.com/sqlteam/xk6-sql", "sql", "db"))
+
+	require.ErrorIs(t, r.scan(dir), errRenameConflict)
+}
+
+func Test_renamer_apply(t *testing.T) {
+	t.Parallel()
+
+	dir := renameFixture(t)
+	rt, _, _ := testRuntime(nil)
+	exec := rt.executor.(*scaffoldtest.Executor) //nolint:forcetypeassert
+
+	exec.Outputs["^git remote get-url origin$"] = "git@github.com:sqlteam/xk6-sql.git\n"
+
+	opts := &options{}
+	opts.Dir = dir
+
+	r := newRenamer(renameOptions("github.com/sqlteam/xk6-sql", "sql", "db"))
+
+	require.NoError(t, r.scan(dir))
+	require.NoError(t, r.apply(newSession(context.Background(), opts, rt)))
+
+	assert.NoFileExists(t, filepath.Join(dir, "sql.go"))
+	assert.FileExists(t, filepath.Join(dir, "db.go"))
+	assert.FileExists(t, filepath.Join(dir, "sqlite.go"))
+	assert.FileExists(t, filepath.Join(dir, "db", "db.go"))
+	assert.NoDirExists(t, filepath.Join(dir, "sql"))
+
+	sum, err := os.ReadFile(filepath.Join(dir, "go.sum"))
+	require.NoError(t, err)
+	assert.Contains(t, string(sum), "github.com/sqlteam/xk6-sql-driver v0.1.0")
+
+	assert.Contains(t, exec.Lines(), "git remote set-url origin git@github.com:sqlteam/xk6-db.git")
+}
+
+func Test_renameOptions_gitlab(t *testing.T) {
+	t.Parallel()
+
+	from, to := renameOptions("gitlab.com/acme/xk6-foo", "foo", "bar")
+
+	assert.Equal(t, "gitlab.com", from.RepoHost)
+	assert.Equal(t, "git@gitlab.com:acme/xk6-foo.git", from.GitOrigin)
+	assert.Equal(t, "git@gitlab.com:acme/xk6-bar.git", to.GitOrigin)
+	assert.Equal(t, "gitlab.com/acme/xk6-bar", to.GoModule)
+}
+
+func Test_renameOptions_nested(t *testing.T) {
+	t.Parallel()
+
+	from, to := renameOptions("gitlab.com/acme/k6/team/xk6-foo", "foo", "bar")
+
+	assert.Equal(t, "acme/k6/team", from.RepoOwner)
+	assert.Equal(t, "git@gitlab.com:acme/k6/team/xk6-bar.git", to.GitOrigin)
+	assert.Equal(t, "gitlab.com/acme/k6/team/xk6-bar", to.GoModule)
+}
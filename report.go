@@ -0,0 +1,284 @@
+//nolint:forbidigo
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/AlecAivazis/survey/v2/terminal"
+	"github.com/briandowns/spinner"
+	"github.com/fatih/color"
+	"github.com/mgutz/ansi"
+	"github.com/szkiba/create-k6-extension/scaffold"
+)
+
+// reporter reports the progress and the result of the steps.
+type reporter interface {
+	// begin reports the start of a series of steps.
+	begin(title string)
+	// start reports the start of a step.
+	start(step string)
+	// finish reports the end of a step with the captured command output and the error (if any).
+	finish(step string, elapsed time.Duration, out []byte, err error)
+	// retry reports a failed attempt of a step, which is retried after the delay.
+	retry(step string, attempt, attempts int, delay time.Duration, err error)
+	// done reports the result of a successful creation.
+	done(res *scaffold.Result)
+}
+
+// notify returns a scaffold event callback, which forwards the events to the reporter.
+func notify(r reporter) func(*scaffold.Event) {
+	return func(e *scaffold.Event) {
+		switch e.Type {
+		case scaffold.EventBegin:
+			r.begin(e.Title)
+		case scaffold.EventStart:
+			r.start(e.Step)
+		case scaffold.EventFinish:
+			r.finish(e.Step, e.Duration, e.Output, e.Err)
+		case scaffold.EventRetry:
+			r.retry(e.Step, e.Attempt, e.Attempts, e.Duration, e.Err)
+		case scaffold.EventResult:
+			r.done(e.Result)
+		}
+	}
+}
+
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+func newReporter(opts *options, rt *runtime) reporter {
+	if opts.outputFormat == outputJSON {
+		return newJSONReporter(rt.Stdio)
+	}
+
+	if !rt.terminal() {
+		return newLogReporter(opts, rt.Stdio)
+	}
+
+	return newTextReporter(opts, rt.Stdio)
+}
+
+type textReporter struct {
+	*terminal.Stdio
+	spinner *spinner.Spinner
+	debug   bool
+}
+
+func newTextReporter(opts *options, stdio *terminal.Stdio) *textReporter {
+	writer := spinner.WithWriter(stdio.Out)
+	if file, ok := stdio.Out.(*os.File); ok {
+		writer = spinner.WithWriterFile(file)
+	}
+
+	return &textReporter{
+		Stdio:   stdio,
+		debug:   opts.debug,
+		spinner: spinner.New(spinner.CharSets[28], 200*time.Millisecond, spinner.WithColor("yellow"), writer),
+	}
+}
+
+func (r *textReporter) print(format string, a ...any) {
+	fmt.Fprintf(r.Out, format, a...)
+}
+
+func (r *textReporter) begin(title string) {
+	r.print("\n\n%s\n", ansi.Color(title, "yellow+b"))
+}
+
+func (r *textReporter) start(step string) {
+	r.spinner.Suffix = " " + step
+	r.spinner.FinalMSG = ansi.Color("✓", "green") + " " + step + "\n"
+	r.spinner.Start()
+}
+
+func (r *textReporter) finish(step string, _ time.Duration, out []byte, err error) {
+	if err != nil {
+		r.spinner.FinalMSG = ansi.Color("✗", "red") + " " + step + "\n"
+	}
+
+	r.spinner.Stop()
+
+	if len(out) != 0 && (err != nil || r.debug) {
+		r.print(color.New(color.Italic).Sprint(string(out)))
+	}
+}
+
+// retryMessage returns the description of a failed attempt.
+func retryMessage(step string, attempt, attempts int, delay time.Duration, err error) string {
+	return fmt.Sprintf("%s: attempt %d of %d failed: %s, retrying in %s", step, attempt, attempts, err, delay)
+}
+
+// retry prints the failed attempt above the spinner, which keeps running.
+func (r *textReporter) retry(step string, attempt, attempts int, delay time.Duration, err error) {
+	msg := ansi.Color("↻", "yellow") + " " + retryMessage(step, attempt, attempts, delay, err) + "\n"
+
+	if !r.spinner.Active() {
+		r.print("%s", msg)
+
+		return
+	}
+
+	final := r.spinner.FinalMSG
+
+	r.spinner.FinalMSG = msg
+	r.spinner.Stop()
+	r.spinner.FinalMSG = final
+	r.spinner.Start()
+}
+
+func (r *textReporter) done(res *scaffold.Result) {
+	r.print("\n%s\n",
+		ansi.Color("Congratulations, the extension is ready!", "green"),
+	)
+	r.print("You can find the initial version of your new extension in:\n  %s\n",
+		ansi.Color(res.Dir, "yellow"),
+	)
+	if len(res.RepoURL) != 0 {
+		r.print("The repository of the extension is available at:\n  %s\n",
+			ansi.Color(res.RepoURL, "cyan"),
+		)
+	}
+
+	r.print("For more information on extension development, visit:\n  %s\n",
+		ansi.Color("https://grafana.com/docs/k6/latest/extensions/create/", "cyan"),
+	)
+
+	if res.Kind != scaffold.JavaScript {
+		return
+	}
+
+	r.print("Use the following commands to build k6 with the %s extension:\n  %s\n  %s\n",
+		ansi.Color(res.Name, "yellow"),
+		ansi.Color("cd "+res.Dir, "yellow"),
+		ansi.Color(res.BuildCommand, "yellow"),
+	)
+
+	r.print("You can test the extension with the following command:\n  %s\n",
+		ansi.Color("./k6 run test.js", "yellow"),
+	)
+
+	r.print(
+		`The TypeScript definition of the extension API can be found in:
+  %s
+The source code and README.md can be regenerated with the following command:
+  %s
+`,
+		ansi.Color("index.d.ts", "yellow"),
+		ansi.Color("go generate", "yellow"),
+	)
+	r.print("See the documentation for more information:\n  %s\n",
+		ansi.Color("https://github.com/szkiba/create-k6-extension/", "cyan"),
+	)
+}
+
+// logReporter reports the progress as plain timestamped lines, for non-terminal output (like CI logs).
+type logReporter struct {
+	*textReporter
+	now func() time.Time
+}
+
+func newLogReporter(opts *options, stdio *terminal.Stdio) *logReporter {
+	return &logReporter{textReporter: &textReporter{Stdio: stdio, debug: opts.debug}, now: time.Now}
+}
+
+func (r *logReporter) log(format string, a ...any) {
+	r.print("%s %s\n", r.now().Format(time.RFC3339), fmt.Sprintf(format, a...))
+}
+
+func (r *logReporter) begin(title string) {
+	r.log("%s", title)
+}
+
+func (r *logReporter) start(step string) {
+	r.log("%s ...", step)
+}
+
+func (r *logReporter) finish(step string, elapsed time.Duration, out []byte, err error) {
+	if err != nil {
+		r.log("%s failed (%s): %s", step, elapsed.Round(time.Millisecond), err)
+	} else {
+		r.log("%s done (%s)", step, elapsed.Round(time.Millisecond))
+	}
+
+	if len(out) != 0 && (err != nil || r.debug) {
+		r.print("%s", out)
+	}
+}
+
+func (r *logReporter) retry(step string, attempt, attempts int, delay time.Duration, err error) {
+	r.log("%s", retryMessage(step, attempt, attempts, delay, err))
+}
+
+// event is an entry of the JSON event stream.
+type event struct {
+	Event    string           `json:"event"`
+	Time     time.Time        `json:"time"`
+	Title    string           `json:"title,omitempty"`
+	Step     string           `json:"step,omitempty"`
+	Duration float64          `json:"duration,omitempty"`
+	Attempt  int              `json:"attempt,omitempty"`
+	Attempts int              `json:"attempts,omitempty"`
+	Output   string           `json:"output,omitempty"`
+	Error    string           `json:"error,omitempty"`
+	Result   *scaffold.Result `json:"result,omitempty"`
+}
+
+// jsonReporter emits the progress and the result as a stream of JSON objects, one object per line.
+type jsonReporter struct {
+	encoder *json.Encoder
+	now     func() time.Time
+}
+
+func newJSONReporter(stdio *terminal.Stdio) *jsonReporter {
+	return &jsonReporter{encoder: json.NewEncoder(stdio.Out), now: time.Now}
+}
+
+func (r *jsonReporter) emit(e *event) {
+	e.Time = r.now()
+
+	_ = r.encoder.Encode(e) //nolint:errchkjson
+}
+
+func (r *jsonReporter) begin(title string) {
+	r.emit(&event{Event: "begin", Title: title})
+}
+
+func (r *jsonReporter) start(step string) {
+	r.emit(&event{Event: "start", Step: step})
+}
+
+func (r *jsonReporter) finish(step string, elapsed time.Duration, out []byte, err error) {
+	e := &event{Event: "finish", Step: step, Duration: elapsed.Seconds(), Output: string(out)}
+
+	if err != nil {
+		e.Error = err.Error()
+	}
+
+	r.emit(e)
+}
+
+// retry emits a retry event, its duration is the delay before the next attempt.
+func (r *jsonReporter) retry(step string, attempt, attempts int, delay time.Duration, err error) {
+	r.emit(&event{
+		Event:    "retry",
+		Step:     step,
+		Duration: delay.Seconds(),
+		Attempt:  attempt,
+		Attempts: attempts,
+		Error:    err.Error(),
+	})
+}
+
+func (r *jsonReporter) done(res *scaffold.Result) {
+	r.emit(&event{Event: "result", Result: res})
+}
+
+// failed emits the error event, the last event of a failed run.
+func (r *jsonReporter) failed(err error) {
+	r.emit(&event{Event: "error", Error: err.Error()})
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AlecAivazis/survey/v2/terminal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/szkiba/create-k6-extension/scaffold"
+)
+
+func decodeEvents(t *testing.T, rt *runtime) []*event {
+	t.Helper()
+
+	var events []*event
+
+	scanner := bufio.NewScanner(strings.NewReader(rt.Out.(bufferWriter).String())) //nolint:forcetypeassert
+
+	for scanner.Scan() {
+		e := new(event)
+
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), e), scanner.Text())
+
+		events = append(events, e)
+	}
+
+	return events
+}
+
+func Test_create_json(t *testing.T) {
+	t.Parallel()
+
+	rt, _ := fakeCreateRuntime(t)
+	opts := fakeCreateOptions(t)
+
+	opts.installed = true
+	opts.outputFormat = outputJSON
+
+	require.NoError(t, create(context.Background(), opts, rt))
+
+	events := decodeEvents(t, rt)
+
+	require.Len(t, events, 2+2*8)
+
+	assert.Equal(t, "begin", events[0].Event)
+	assert.Equal(t, "Creating extension", events[0].Title)
+
+	for idx := 1; idx < len(events)-1; idx += 2 {
+		assert.Equal(t, "start", events[idx].Event)
+		assert.Equal(t, "finish", events[idx+1].Event)
+		assert.Equal(t, events[idx].Step, events[idx+1].Step)
+		assert.Empty(t, events[idx+1].Error)
+		assert.False(t, events[idx+1].Time.Before(events[idx].Time))
+	}
+
+	assert.Equal(t, "Download template", events[1].Step)
+
+	last := events[len(events)-1]
+
+	require.Equal(t, "result", last.Event)
+	assert.Equal(t, &scaffold.Result{
+		Kind:         scaffold.JavaScript,
+		Name:         "foo",
+		Dir:          opts.Dir,
+		GoModule:     "github.com/acme/xk6-foo",
+		GitOrigin:    "git@github.com:acme/xk6-foo.git",
+		BuildCommand: "xk6 build --with github.com/acme/xk6-foo=.",
+	}, last.Result)
+}
+
+func Test_create_json_failure(t *testing.T) {
+	t.Parallel()
+
+	rt, exec := fakeCreateRuntime(t)
+	opts := fakeCreateOptions(t)
+
+	opts.installed = true
+	opts.outputFormat = outputJSON
+
+	exec.Failures["^go generate"] = errors.New("fake failure")
+	exec.Outputs["^go generate"] = "generate: something is wrong\n"
+
+	require.Error(t, create(context.Background(), opts, rt))
+
+	events := decodeEvents(t, rt)
+	last := events[len(events)-1]
+
+	assert.Equal(t, "finish", last.Event)
+	assert.Equal(t, "Generate sources", last.Step)
+	assert.Equal(t, "fake failure", last.Error)
+	assert.Equal(t, "generate: something is wrong\n", last.Output)
+}
+
+func Test_logReporter(t *testing.T) {
+	t.Parallel()
+
+	var buff bytes.Buffer
+
+	r := newLogReporter(&options{}, &terminal.Stdio{Out: bufferWriter{&buff}})
+	r.now = func() time.Time { return time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC) }
+
+	r.begin("Creating extension")
+	r.start("Download template")
+	r.finish("Download template", 1234*time.Millisecond, []byte("cloned\n"), nil)
+	r.start("Generate sources")
+	r.retry("Generate sources", 1, 2, time.Second, errors.New("proxy error"))
+	r.finish("Generate sources", 50*time.Millisecond, []byte("something is wrong\n"), errors.New("exit status 1"))
+
+	assert.Equal(t, `2024-01-02T10:00:00Z Creating extension
+2024-01-02T10:00:00Z Download template ...
+2024-01-02T10:00:00Z Download template done (1.234s)
+2024-01-02T10:00:00Z Generate sources ...
+2024-01-02T10:00:00Z Generate sources: attempt 1 of 2 failed: proxy error, retrying in 1s
+2024-01-02T10:00:00Z Generate sources failed (50ms): exit status 1
+something is wrong
+`, buff.String())
+}
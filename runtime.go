@@ -1,41 +1,164 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 
+	"github.com/AlecAivazis/survey/v2/core"
 	"github.com/AlecAivazis/survey/v2/terminal"
+	"github.com/fatih/color"
 	"github.com/mgutz/ansi"
+	"github.com/spf13/pflag"
+	"github.com/szkiba/create-k6-extension/scaffold"
+	"golang.org/x/term"
 )
 
 type runtime struct {
 	*terminal.Stdio
-	args     []string
-	exit     func(int)
-	lookPath func(string) (string, error)
+	args      []string
+	exit      func(int)
+	lookPath  func(string) (string, error)
+	getenv    func(string) string
+	executor  scaffold.Executor
+	templates *scaffold.Prefetcher
+	colorMode string
+	// output is the output format, the messages must not corrupt the JSON stream
+	output string
 }
 
 //nolint:forbidigo
 func stdRuntime() *runtime {
+	cmd := scaffold.NewExecutor()
+
 	return &runtime{
 		Stdio: &terminal.Stdio{
 			In:  os.Stdin,
 			Out: os.Stdout,
 			Err: os.Stderr,
 		},
-		args:     os.Args,
-		exit:     os.Exit,
-		lookPath: exec.LookPath,
+		args:      os.Args,
+		exit:      os.Exit,
+		lookPath:  exec.LookPath,
+		getenv:    os.Getenv,
+		executor:  cmd,
+		templates: scaffold.NewPrefetcher(cmd),
+	}
+}
+
+// terminal returns true if the output is a terminal.
+func (rt *runtime) terminal() bool {
+	return term.IsTerminal(int(rt.Out.Fd()))
+}
+
+// parse parses the command line flags and applies the common flags.
+func (rt *runtime) parse(flags *pflag.FlagSet, args []string) error {
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	return rt.setColor()
+}
+
+// presetColor resolves the color mode from the command line before anything is printed,
+// even before the flags are parsed (the usage and the flag errors are printed by the parsing itself).
+// An invalid --color value is reported later, by the parsing.
+func (rt *runtime) presetColor() {
+	rt.colorMode = colorFlag(rt.args)
+
+	if rt.setColor() != nil {
+		rt.colorMode = colorAuto
+
+		_ = rt.setColor()
 	}
 }
 
+// colorFlag returns the value of the --color flag, ignoring the other flags.
+func colorFlag(args []string) string {
+	flags := pflag.NewFlagSet("color", pflag.ContinueOnError)
+
+	flags.ParseErrorsWhitelist.UnknownFlags = true
+	flags.SetOutput(io.Discard)
+	flags.Usage = func() {}
+
+	mode := flags.String("color", colorAuto, "")
+
+	if len(args) != 0 {
+		_ = flags.Parse(args[1:])
+	}
+
+	return *mode
+}
+
+// setColor enables or disables colored output based on the --color flag,
+// the NO_COLOR environment variable and whether the output is a terminal.
+func (rt *runtime) setColor() error {
+	enabled, err := colorEnabled(rt.colorMode, rt.getenv("NO_COLOR"), rt.terminal())
+	if err != nil {
+		return err
+	}
+
+	ansi.DisableColors(!enabled)
+	color.NoColor = !enabled
+	core.DisableColor = !enabled
+
+	return nil
+}
+
+func colorEnabled(mode string, noColor string, terminal bool) (bool, error) {
+	switch mode {
+	case colorAlways:
+		return true, nil
+	case colorNever:
+		return false, nil
+	case colorAuto, "":
+		return len(noColor) == 0 && terminal, nil
+	default:
+		return false, fmt.Errorf("%w: color: %s", errInvalidFlag, mode)
+	}
+}
+
+const (
+	colorAuto   = "auto"
+	colorAlways = "always"
+	colorNever  = "never"
+)
+
 func (rt *runtime) fail(err error) {
+	// the consumers of the JSON stream get the error, even if it happened before the first step
+	if rt.output == outputJSON {
+		newJSONReporter(rt.Stdio).failed(err)
+	}
+
+	if errors.Is(err, context.Canceled) {
+		fmt.Fprintf(rt.Err, "error: interrupted\n")
+		rt.exit(exitInterrupted)
+
+		return
+	}
+
+	var verrs scaffold.ValidationErrors
+
+	if errors.As(err, &verrs) {
+		for _, verr := range verrs {
+			fmt.Fprintf(rt.Err, "error: invalid %s\n", verr.Error())
+		}
+
+		rt.exit(1)
+
+		return
+	}
+
 	fmt.Fprintf(rt.Err, "error: %s\n", err.Error())
 	rt.exit(1)
 }
 
+// exitInterrupted is the conventional exit code after SIGINT (128 + 2).
+const exitInterrupted = 130
+
 func (rt *runtime) prerequisite() {
 	rt.require(
 		"go",
@@ -48,15 +171,18 @@ func (rt *runtime) prerequisite() {
 		"To use this command, you need the git CLI!",
 		"https://git-scm.com/downloads",
 	)
+
+	rt.requireVersion("go", "https://go.dev/doc/install", "version")
+	rt.requireVersion("git", "https://git-scm.com/downloads", "--version")
 }
 
 func (rt *runtime) require(cmd, msg, link string) {
-	if _, err := rt.lookPath("git"); err == nil {
+	if _, err := rt.lookPath(cmd); err == nil {
 		return
 	}
 
 	fmt.Fprintf(
-		terminal.NewAnsiStderr(rt.Out),
+		rt.messages(),
 		"%s\n%s\n",
 		ansi.Color(msg, "red"),
 		ansi.Color(link, "cyan"),
@@ -65,4 +191,55 @@ func (rt *runtime) require(cmd, msg, link string) {
 	rt.fail(fmt.Errorf("%w: %s", errPrerequisite, cmd))
 }
 
-var errPrerequisite = errors.New("missing prerequisite")
+// requireVersion checks the version of the tool against the minimum version declared by the templates.
+func (rt *runtime) requireVersion(cmd, link string, args ...string) {
+	required := scaffold.MinVersion(cmd)
+	if len(required) == 0 {
+		return
+	}
+
+	out, err := rt.executor.Output(context.Background(), "", cmd, args...)
+	if err != nil {
+		rt.fail(err)
+
+		return
+	}
+
+	version, err := scaffold.ParseVersion(string(out))
+	if err != nil {
+		rt.fail(err)
+
+		return
+	}
+
+	if scaffold.CompareVersions(version, required) >= 0 {
+		return
+	}
+
+	fmt.Fprintf(
+		rt.messages(),
+		"%s\n%s\n",
+		ansi.Color(
+			fmt.Sprintf("To use this command, you need %s %s or newer (found %s), please upgrade!", cmd, required, version),
+			"red",
+		),
+		ansi.Color(link, "cyan"),
+	)
+
+	rt.fail(fmt.Errorf("%w: %s %s", errOutdatedPrerequisite, cmd, version))
+}
+
+// messages returns the writer of the prerequisite messages:
+// the output, unless it is a JSON stream, which must contain only JSON objects.
+func (rt *runtime) messages() io.Writer {
+	if rt.output == outputJSON {
+		return rt.Err
+	}
+
+	return terminal.NewAnsiStderr(rt.Out)
+}
+
+var (
+	errPrerequisite         = errors.New("missing prerequisite")
+	errOutdatedPrerequisite = errors.New("outdated prerequisite")
+)
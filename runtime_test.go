@@ -0,0 +1,196 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/AlecAivazis/survey/v2/terminal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/szkiba/create-k6-extension/scaffold"
+	"github.com/szkiba/create-k6-extension/scaffold/scaffoldtest"
+)
+
+type bufferWriter struct {
+	*bytes.Buffer
+}
+
+// Fd returns an invalid file descriptor, so the buffer is never considered a terminal.
+func (bufferWriter) Fd() uintptr {
+	return ^uintptr(0)
+}
+
+func testRuntime(versions map[string]string) (*runtime, *bytes.Buffer, *int) {
+	var stderr bytes.Buffer
+
+	code := -1
+
+	exec := scaffoldtest.NewExecutor()
+
+	for name, version := range versions {
+		exec.Outputs["^"+name+" "] = version
+	}
+
+	rt := &runtime{
+		Stdio: &terminal.Stdio{Out: bufferWriter{&stderr}, Err: &stderr},
+		exit:  func(c int) { code = c },
+		lookPath: func(name string) (string, error) {
+			if _, found := versions[name]; found {
+				return "/usr/bin/" + name, nil
+			}
+
+			return "", errors.ErrUnsupported
+		},
+		executor: exec,
+	}
+
+	return rt, &stderr, &code
+}
+
+func Test_runtime_prerequisite(t *testing.T) {
+	t.Parallel()
+
+	rt, _, code := testRuntime(map[string]string{
+		"go":  "go version go1.21.5 linux/amd64",
+		"git": "git version 2.39.2",
+	})
+
+	rt.prerequisite()
+
+	assert.Equal(t, -1, *code)
+}
+
+func Test_runtime_prerequisite_missing(t *testing.T) {
+	t.Parallel()
+
+	rt, stderr, code := testRuntime(map[string]string{
+		"git": "git version 2.39.2",
+	})
+
+	rt.require("go", "need go", "https://go.dev/doc/install")
+
+	assert.Equal(t, 1, *code)
+	assert.Contains(t, stderr.String(), "missing prerequisite: go")
+}
+
+func Test_runtime_prerequisite_outdated(t *testing.T) {
+	t.Parallel()
+
+	rt, stderr, code := testRuntime(map[string]string{
+		"go":  "go version go1.21.5 linux/amd64",
+		"git": "git version 2.20.1",
+	})
+
+	rt.prerequisite()
+
+	assert.Equal(t, 1, *code)
+	assert.Contains(t, stderr.String(), "git 2.28 or newer (found 2.20.1)")
+	assert.Contains(t, stderr.String(), "outdated prerequisite: git 2.20.1")
+}
+
+func Test_colorEnabled(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		mode     string
+		noColor  string
+		terminal bool
+		expected bool
+	}{
+		{mode: colorAuto, terminal: true, expected: true},
+		{mode: colorAuto, terminal: false, expected: false},
+		{mode: colorAuto, noColor: "1", terminal: true, expected: false},
+		{mode: "", terminal: true, expected: true},
+		{mode: colorAlways, noColor: "1", terminal: false, expected: true},
+		{mode: colorNever, terminal: true, expected: false},
+	}
+
+	for _, tt := range tests {
+		enabled, err := colorEnabled(tt.mode, tt.noColor, tt.terminal)
+
+		assert.NoError(t, err)
+		assert.Equal(t, tt.expected, enabled, "%+v", tt)
+	}
+
+	_, err := colorEnabled("sometimes", "", true)
+
+	assert.ErrorIs(t, err, errInvalidFlag)
+}
+
+func Test_colorFlag(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string][]string{
+		colorAuto:   {_appname, "--name", "foo", "--no-ask"},
+		colorNever:  {_appname, "--no-ask", "--color", "never", "--name", "foo"},
+		colorAlways: {_appname, "rename", "--dry-run", "--color=always", "foo", "bar"},
+		"sometimes": {_appname, "--color", "sometimes", "--help"},
+	}
+
+	for expected, args := range tests {
+		assert.Equal(t, expected, colorFlag(args), "%v", args)
+	}
+
+	assert.Equal(t, colorAuto, colorFlag(nil))
+}
+
+func Test_runtime_fail_interrupted(t *testing.T) {
+	t.Parallel()
+
+	rt, stderr, code := testRuntime(nil)
+
+	rt.fail(fmt.Errorf("Generate sources: %w", context.Canceled))
+
+	assert.Equal(t, exitInterrupted, *code)
+	assert.Equal(t, "error: interrupted\n", stderr.String())
+}
+
+func Test_runtime_prerequisite_json(t *testing.T) {
+	t.Parallel()
+
+	rt, stderr, code := testRuntime(map[string]string{
+		"git": "git version 2.39.2",
+	})
+
+	var stdout bytes.Buffer
+
+	rt.Out = bufferWriter{&stdout}
+	rt.output = outputJSON
+
+	rt.require("go", "need go", "https://go.dev/doc/install")
+
+	assert.Equal(t, 1, *code)
+	assert.Contains(t, stderr.String(), "need go")
+	assert.Contains(t, stderr.String(), "error: missing prerequisite: go")
+
+	// the output contains only the error event
+	events := decodeEvents(t, rt)
+
+	require.Len(t, events, 1)
+	assert.Equal(t, "error", events[0].Event)
+	assert.Equal(t, "missing prerequisite: go", events[0].Error)
+}
+
+func Test_runtime_fail_json(t *testing.T) {
+	t.Parallel()
+
+	rt, stderr, _ := testRuntime(nil)
+
+	var stdout bytes.Buffer
+
+	rt.Out = bufferWriter{&stdout}
+	rt.output = outputJSON
+
+	rt.fail(scaffold.ValidationErrors{{Option: "name", Value: "Foo", Err: errors.New("lower case is required")}})
+
+	assert.Equal(t, "error: invalid name \"Foo\": lower case is required\n", stderr.String())
+
+	events := decodeEvents(t, rt)
+
+	require.Len(t, events, 1)
+	assert.Equal(t, "error", events[0].Event)
+	assert.Equal(t, `invalid options: name "Foo": lower case is required`, events[0].Error)
+}
@@ -0,0 +1,172 @@
+//nolint:forbidigo
+package scaffold
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// CatalogEntry describes a known extension.
+type CatalogEntry struct {
+	// Module is the go module path of the extension.
+	Module string `json:"module"`
+	// Imports contains the JavaScript module names of the extension (like k6/x/sql).
+	Imports []string `json:"imports,omitempty"`
+	// Outputs contains the output names of the extension (like the name passed to the k6 run --out flag).
+	Outputs []string `json:"outputs,omitempty"`
+}
+
+// Catalog contains the known extensions, to avoid creating an extension with an already used name.
+type Catalog []*CatalogEntry
+
+//go:embed catalog.json
+var bundledCatalog []byte //nolint:gochecknoglobals
+
+// BundledCatalog returns the snapshot of the known extensions bundled with the package.
+func BundledCatalog() Catalog {
+	catalog, err := ParseCatalog(bundledCatalog)
+	if err != nil {
+		panic(err)
+	}
+
+	return catalog
+}
+
+// ParseCatalog parses a catalog in JSON format.
+func ParseCatalog(data []byte) (Catalog, error) {
+	var catalog Catalog
+
+	if err := json.Unmarshal(data, &catalog); err != nil {
+		return nil, fmt.Errorf("%w: %s", errInvalidCatalog, err.Error())
+	}
+
+	return catalog, nil
+}
+
+// LoadCatalog loads a catalog in JSON format from a local file or from an http(s) URL.
+func LoadCatalog(ctx context.Context, location string) (Catalog, error) {
+	if !strings.HasPrefix(location, "https://") && !strings.HasPrefix(location, "http://") {
+		data, err := os.ReadFile(location)
+		if err != nil {
+			return nil, err
+		}
+
+		return ParseCatalog(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %s: %s", errInvalidCatalog, location, resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseCatalog(data)
+}
+
+// Lookup returns the known extension that already uses the name of the given type of extension, or nil.
+// The name of a JavaScript extension is compared with the k6/x/ module names, the name of an Output extension
+// with the output names.
+func (c Catalog) Lookup(k Kind, name string) *CatalogEntry {
+	for _, entry := range c {
+		names := entry.Outputs
+
+		if k == JavaScript {
+			names = make([]string, 0, len(entry.Imports))
+
+			for _, imp := range entry.Imports {
+				names = append(names, strings.TrimPrefix(imp, jsModulePrefix))
+			}
+		}
+
+		for _, used := range names {
+			if used == name {
+				return entry
+			}
+		}
+	}
+
+	return nil
+}
+
+// Check returns a *NameCollisionError with alternative names if the name is already used by a known extension.
+func (c Catalog) Check(k Kind, name string) error {
+	entry := c.Lookup(k, name)
+	if entry == nil {
+		return nil
+	}
+
+	return &NameCollisionError{Name: name, Entry: entry, Suggestions: c.suggest(k, name)}
+}
+
+// suggest returns valid alternative names not used by any known extension.
+func (c Catalog) suggest(k Kind, name string) []string {
+	candidates := []string{name + "x", "my" + name, name + "ext", name + "kit", "x" + name}
+	suggestions := make([]string, 0, maxSuggestions)
+
+	for _, candidate := range candidates {
+		if len(suggestions) == maxSuggestions {
+			break
+		}
+
+		if CheckName(candidate) == nil && c.Lookup(k, candidate) == nil {
+			suggestions = append(suggestions, candidate)
+		}
+	}
+
+	return suggestions
+}
+
+// NameCollisionError is returned if the extension name is already used by a known extension.
+type NameCollisionError struct {
+	Name        string
+	Entry       *CatalogEntry
+	Suggestions []string
+}
+
+func (e *NameCollisionError) Error() string {
+	msg := fmt.Sprintf("%s: %s is already used by %s", ErrNameCollision, e.Name, e.Entry.Module)
+
+	if len(e.Suggestions) != 0 {
+		msg += ", try " + strings.Join(e.Suggestions, ", ")
+	}
+
+	return msg
+}
+
+func (e *NameCollisionError) Unwrap() error {
+	return ErrNameCollision
+}
+
+const (
+	jsModulePrefix = "k6/x/"
+	maxSuggestions = 3
+)
+
+var (
+	// ErrNameCollision is the cause of NameCollisionError.
+	ErrNameCollision = errors.New("name collision")
+
+	errInvalidCatalog = errors.New("invalid extension catalog")
+)
@@ -0,0 +1,103 @@
+package scaffold
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_BundledCatalog(t *testing.T) {
+	t.Parallel()
+
+	catalog := BundledCatalog()
+
+	require.NotEmpty(t, catalog)
+
+	entry := catalog.Lookup(JavaScript, "sql")
+
+	require.NotNil(t, entry)
+	assert.Equal(t, "github.com/grafana/xk6-sql", entry.Module)
+
+	assert.Nil(t, catalog.Lookup(Output, "sql"))
+	assert.NotNil(t, catalog.Lookup(Output, "json"))
+	assert.Nil(t, catalog.Lookup(JavaScript, "json"))
+}
+
+func Test_Catalog_Check(t *testing.T) {
+	t.Parallel()
+
+	catalog := Catalog{
+		{Module: "github.com/acme/xk6-foo", Imports: []string{"k6/x/foo"}},
+		{Module: "github.com/acme/xk6-foox", Imports: []string{"k6/x/foox"}},
+		{Module: "github.com/acme/xk6-output-bar", Outputs: []string{"bar"}},
+	}
+
+	assert.NoError(t, catalog.Check(JavaScript, "bar"))
+	assert.NoError(t, catalog.Check(Output, "foo"))
+
+	err := catalog.Check(JavaScript, "foo")
+
+	require.ErrorIs(t, err, ErrNameCollision)
+
+	var cerr *NameCollisionError
+
+	require.ErrorAs(t, err, &cerr)
+	assert.Equal(t, "github.com/acme/xk6-foo", cerr.Entry.Module)
+	assert.Equal(t, []string{"myfoo", "fooext", "fookit"}, cerr.Suggestions)
+	assert.Equal(t, "name collision: foo is already used by github.com/acme/xk6-foo, try myfoo, fooext, fookit", err.Error())
+
+	require.ErrorAs(t, catalog.Check(Output, "bar"), &cerr)
+	assert.Equal(t, []string{"barx", "mybar", "barext"}, cerr.Suggestions)
+
+	var empty Catalog
+
+	assert.NoError(t, empty.Check(JavaScript, "foo"))
+}
+
+func Test_LoadCatalog(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`[{"module":"github.com/acme/xk6-foo","imports":["k6/x/foo"]}]`)
+
+	file := filepath.Join(t.TempDir(), "catalog.json")
+
+	require.NoError(t, os.WriteFile(file, data, 0o600))
+
+	catalog, err := LoadCatalog(context.Background(), file)
+
+	require.NoError(t, err)
+	assert.NotNil(t, catalog.Lookup(JavaScript, "foo"))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/catalog.json" {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		_, _ = w.Write(data)
+	}))
+
+	defer srv.Close()
+
+	catalog, err = LoadCatalog(context.Background(), srv.URL+"/catalog.json")
+
+	require.NoError(t, err)
+	assert.NotNil(t, catalog.Lookup(JavaScript, "foo"))
+
+	_, err = LoadCatalog(context.Background(), srv.URL+"/missing.json")
+
+	assert.ErrorIs(t, err, errInvalidCatalog)
+
+	require.NoError(t, os.WriteFile(file, []byte("{"), 0o600))
+
+	_, err = LoadCatalog(context.Background(), file)
+
+	assert.ErrorIs(t, err, errInvalidCatalog)
+}
@@ -0,0 +1,151 @@
+//nolint:forbidigo
+package scaffold
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/valyala/fasttemplate"
+)
+
+// The CI platforms of the generated CI workflows.
+const (
+	// CIGitHub is GitHub Actions.
+	CIGitHub = "github"
+	// CIGitLab is GitLab CI/CD.
+	CIGitLab = "gitlab"
+	// CIAuto selects the CI platform of the repository hosting service.
+	CIAuto = "auto"
+)
+
+//go:embed all:ci
+var ciFiles embed.FS
+
+// CIPlatform returns the CI platform of the generated CI workflows, resolving CIAuto by the hosting service.
+// An empty value means that the CI workflows of the template are kept.
+// There is no CI platform for Gitea, because the release workflow relies on the GitHub API.
+func (opts *Options) CIPlatform() string {
+	if opts.CI != CIAuto {
+		return opts.CI
+	}
+
+	switch opts.Host().Provider {
+	case ProviderGitHub:
+		return CIGitHub
+	case ProviderGitLab:
+		return CIGitLab
+	case ProviderGitea, ProviderBitbucket:
+	}
+
+	return ""
+}
+
+// CheckCI checks that the CI platform is supported.
+func CheckCI(ci string) error {
+	switch ci {
+	case "", CIGitHub, CIGitLab, CIAuto:
+		return nil
+	default:
+		return errInvalidCI
+	}
+}
+
+// generateCI writes the CI workflows of the selected platform: lint, test, xk6 build and smoke test on push,
+// and building the k6 binaries of a release on version tags.
+// The GitHub Actions workflows of the template are removed, whichever platform is selected.
+func (c *creator) generateCI() error {
+	platform := c.opts.CIPlatform()
+
+	data, err := c.ciData()
+	if err != nil {
+		return err
+	}
+
+	github := filepath.Join(c.opts.Dir, ".github")
+
+	if err := os.RemoveAll(filepath.Join(github, "workflows")); err != nil {
+		return err
+	}
+
+	_ = os.Remove(github) // only if nothing else is left in it
+
+	root := path.Join("ci", platform)
+
+	return fs.WalkDir(ciFiles, root, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+
+		content, err := ciFiles.ReadFile(name)
+		if err != nil {
+			return err
+		}
+
+		var buff bytes.Buffer
+
+		if _, err = fasttemplate.ExecuteStd(string(content), "ˮ", "ˮ", &buff, data); err != nil {
+			return err
+		}
+
+		dst := filepath.Join(c.opts.Dir, filepath.FromSlash(name[len(root)+1:]))
+
+		if err := os.MkdirAll(filepath.Dir(dst), 0o750); err != nil {
+			return err
+		}
+
+		return os.WriteFile(dst, buff.Bytes(), 0o600)
+	})
+}
+
+// ciData returns the template variables extended with the values used by the CI workflows.
+func (c *creator) ciData() (map[string]interface{}, error) {
+	gomod, err := os.ReadFile(filepath.Join(c.opts.Dir, "go.mod"))
+	if err != nil {
+		return nil, err
+	}
+
+	goVersion, err := GoModVersion(gomod)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make(map[string]interface{}, len(c.data)+4)
+
+	for key, value := range c.data {
+		data[key] = value
+	}
+
+	data["goVersion"] = goVersion
+	data["k6Version"] = orLatest(c.opts.K6Version)
+	data["xk6Version"] = orLatest(c.opts.XK6Version)
+
+	smoke := "./k6 run --vus 1 --iterations 1 "
+
+	if c.opts.Kind == JavaScript {
+		data["smokeCommand"] = smoke + "test.js"
+	} else {
+		data["smokeCommand"] = fmt.Sprintf("echo 'export default function () {}' > smoke.js && %s--out %s smoke.js",
+			smoke, c.opts.Name)
+	}
+
+	return data, nil
+}
+
+func orLatest(version string) string {
+	if len(version) == 0 {
+		return "latest"
+	}
+
+	return version
+}
+
+var (
+	errInvalidCI = errors.New("github, gitlab or auto is required")
+	errNoCI      = errors.New("CI workflows are not supported for")
+)
@@ -0,0 +1,97 @@
+package scaffold
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_creator_generateCI(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]*Options{
+		"github": {
+			Kind:         JavaScript,
+			Name:         "foo",
+			RepoOwner:    "acme",
+			RepoProtocol: "ssh",
+			K6Version:    "v0.49.0",
+			CI:           CIAuto,
+		},
+		"gitlab": {
+			Kind:         Output,
+			Name:         "bar",
+			RepoHost:     "gitlab.com",
+			RepoOwner:    "acme/k6",
+			RepoProtocol: "ssh",
+			XK6Version:   "v0.10.0",
+			CI:           CIGitLab,
+		},
+	}
+
+	for name, opts := range tests {
+		name, opts := name, opts
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			opts.Guess()
+			opts.Update()
+			opts.Dir = t.TempDir()
+
+			require.Equal(t, name, opts.CIPlatform())
+
+			workflows := filepath.Join(opts.Dir, ".github", "workflows")
+
+			require.NoError(t, os.MkdirAll(workflows, 0o750))
+			require.NoError(t, os.WriteFile(filepath.Join(workflows, "test.yml"), []byte("name: test\n"), 0o600))
+			require.NoError(t, os.WriteFile(filepath.Join(opts.Dir, "go.mod"), []byte("module "+opts.GoModule+"\n\ngo 1.21\n"), 0o600))
+
+			c, err := newCreator(context.Background(), opts)
+			require.NoError(t, err)
+
+			require.NoError(t, c.generateCI())
+
+			actual := readDir(t, opts.Dir)
+			delete(actual, "go.mod")
+
+			golden := filepath.Join("testdata", "golden", "ci", name)
+
+			assertGolden(t, golden, actual)
+		})
+	}
+}
+
+func Test_Options_CIPlatform(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"":              CIGitHub,
+		"gitlab.com":    CIGitLab,
+		"codeberg.org":  "",
+		"bitbucket.org": "",
+	}
+
+	for host, expected := range tests {
+		opts := &Options{CI: CIAuto, RepoHost: host}
+
+		assert.Equal(t, expected, opts.CIPlatform(), host)
+	}
+
+	opts := &Options{Kind: JavaScript, Name: "foo", RepoHost: "bitbucket.org", RepoOwner: "acme", RepoProtocol: "ssh"}
+
+	opts.Guess()
+
+	opts.CI = CIGitLab
+	require.NoError(t, opts.Validate())
+
+	opts.CI = CIAuto
+	assert.ErrorIs(t, opts.Validate(), errNoCI)
+
+	opts.CI = "jenkins"
+	assert.ErrorIs(t, opts.Validate(), errInvalidCI)
+}
@@ -0,0 +1,592 @@
+//nolint:forbidigo
+package scaffold
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/valyala/fasttemplate"
+)
+
+// Create creates a new extension from the template of the given type.
+// The options must be complete, missing values can be derived with the Options.Guess method.
+// The options are validated before any side effects, the invalid options are returned as ValidationErrors.
+// The progress and the result are also reported to the Options.OnEvent callback.
+// The error of a failed step is returned as *StepError.
+// If the context is canceled, the running command is killed,
+// and the partially created extension directory is removed if it has not been committed yet.
+func Create(ctx context.Context, opts *Options) (*Result, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	c, err := newCreator(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.create()
+}
+
+type creator struct {
+	*Pipeline
+	opts      *Options
+	templates *Prefetcher
+	data      map[string]interface{}
+
+	srcDir string
+
+	// k6 is the k6 binary built with the extension during verification
+	k6 string
+	// pinK6 is true if the k6 version in go.mod should be changed
+	pinK6 bool
+	// created is true if the extension directory has been created
+	created bool
+	// written is true if all the files of the extension have been written (and committed),
+	// so the extension directory is kept even if a later step is canceled
+	written bool
+	// repoURL is the web URL of the GitHub repository, if it has been created or updated
+	repoURL string
+}
+
+func newCreator(ctx context.Context, opts *Options) (*creator, error) {
+	c := new(creator)
+
+	c.Pipeline = NewPipeline(ctx, opts.Executor, opts.OnEvent)
+	c.policy = newPolicy(opts)
+	c.opts = opts
+	c.templates = opts.Templates
+
+	var err error
+	c.data, err = opts.toMap()
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (c *creator) downloadTemplate() error {
+	if c.opts.Kind.template() == nil {
+		return fmt.Errorf("%w: %s", ErrUnknownKind, c.opts.Kind)
+	}
+
+	// the first attempt may use the template prefetched while the questions were answered
+	fetch := c.templates.take(c.opts.Kind)
+
+	var err error
+
+	c.debug, err = c.retry(stepDownloadTemplate, func(ctx context.Context) ([]byte, error) {
+		if fetch == nil {
+			fetch = fetchTemplate(ctx, c.executor, c.opts.Kind)
+		}
+
+		defer func() { fetch = nil }()
+		defer fetch.cancel()
+
+		select {
+		case <-fetch.done:
+		case <-ctx.Done():
+			fetch.discard()
+
+			return fetch.out, ctx.Err()
+		}
+
+		if fetch.err != nil {
+			return fetch.out, fetch.err
+		}
+
+		c.srcDir = fetch.dir
+
+		return fetch.out, nil
+	})
+
+	return err
+}
+
+// checkGoVersion checks the go toolchain against the go version declared by the template
+// and the one required by the template's go.mod (the newer wins),
+// so an outdated toolchain is reported before anything is created.
+func (c *creator) checkGoVersion() error {
+	gomod, err := os.ReadFile(filepath.Join(c.srcDir, "go.mod"))
+	if err != nil {
+		return err
+	}
+
+	required, err := GoModVersion(gomod)
+	if err != nil {
+		return err
+	}
+
+	if declared := c.opts.Kind.template().minVersions["go"]; CompareVersions(declared, required) > 0 {
+		required = declared
+	}
+
+	out, err := c.Output("", "go", "version")
+	if err != nil {
+		return err
+	}
+
+	version, err := ParseVersion(string(out))
+	if err != nil {
+		return err
+	}
+
+	if CompareVersions(version, required) < 0 {
+		return fmt.Errorf("%w: go %s or newer is required by the %s template, found %s, please upgrade",
+			ErrOutdatedGo, required, c.opts.Kind, version)
+	}
+
+	return nil
+}
+
+// resolveK6Version resolves the k6 version to use and makes it available as template variable.
+// Without an explicitly specified version, the version pinned by the template's go.mod is used.
+func (c *creator) resolveK6Version() error {
+	if len(c.opts.K6Version) == 0 {
+		gomod, err := os.ReadFile(filepath.Join(c.srcDir, "go.mod"))
+		if err != nil {
+			return err
+		}
+
+		if match := reK6Require.FindSubmatch(gomod); match != nil {
+			c.opts.K6Version = string(match[1])
+		}
+	} else {
+		var (
+			bin []byte
+			err error
+		)
+
+		c.debug, err = c.retry(stepResolveK6Version, func(ctx context.Context) ([]byte, error) {
+			out, err := c.executor.Output(ctx, c.srcDir, "go", "list", "-m", "-f", "{{.Version}}", k6Module+"@"+c.opts.K6Version)
+			bin = out
+
+			return out, err
+		})
+		if err != nil {
+			return err
+		}
+
+		c.opts.K6Version = strings.TrimSpace(string(bin))
+		c.pinK6 = true
+	}
+
+	var err error
+
+	c.data, err = c.opts.toMap()
+
+	return err
+}
+
+// pinK6Version requires the resolved k6 version, which may download go modules,
+// so it is retried as a network-bound step.
+func (c *creator) pinK6Version() error {
+	var err error
+
+	c.debug, err = c.retry(stepPinK6Version, func(ctx context.Context) ([]byte, error) {
+		return c.executor.Run(ctx, c.opts.Dir, "go", "get", k6Module+"@"+c.opts.K6Version)
+	})
+
+	return err
+}
+
+func (c *creator) expandTemplate() error {
+	bin, oerr := c.Output(c.srcDir, "go", "list", "-m")
+	if oerr != nil {
+		return oerr
+	}
+
+	lines := strings.SplitN(string(bin), "\n", 2)
+
+	srcMod := lines[0]
+
+	oerr = filepath.WalkDir(c.srcDir, func(path string, entry fs.DirEntry, werr error) error {
+		if werr != nil {
+			return werr
+		}
+
+		if path == c.srcDir {
+			if err := os.Mkdir(c.opts.Dir, 0o750); err != nil {
+				return err
+			}
+
+			c.created = true
+
+			return nil
+		}
+
+		var relSrc string
+		var err error
+
+		if relSrc, err = filepath.Rel(c.srcDir, path); err != nil {
+			return err
+		}
+
+		var buff bytes.Buffer
+
+		if _, err = fasttemplate.ExecuteStd(relSrc, "ˮ", "ˮ", &buff, c.data); err != nil {
+			return err
+		}
+
+		dst := filepath.Join(c.opts.Dir, buff.String())
+
+		if entry.IsDir() {
+			return os.Mkdir(dst, 0o750)
+		}
+
+		var bin []byte
+
+		if bin, err = os.ReadFile(filepath.Clean(path)); err != nil {
+			return err
+		}
+
+		buff.Reset()
+
+		if _, err = fasttemplate.ExecuteStd(string(bin), "ˮ", "ˮ", &buff, c.data); err != nil {
+			return err
+		}
+
+		content := bytes.ReplaceAll(buff.Bytes(), []byte(srcMod), []byte(c.opts.GoModule))
+
+		return os.WriteFile(dst, content, 0o600)
+	})
+	if oerr != nil {
+		return oerr
+	}
+
+	if err := os.RemoveAll(c.srcDir); err != nil {
+		return err
+	}
+
+	c.srcDir = ""
+
+	return nil
+}
+
+// writeRegistryEntry writes the metadata required to publish the extension in the k6 extension registry.
+func (c *creator) writeRegistryEntry() error {
+	return WriteRegistryEntry(c.opts.Dir, c.opts.RegistryEntry())
+}
+
+func (c *creator) createGitRepository() error {
+	if err := c.Run("", "git", "init", c.opts.Dir); err != nil {
+		return err
+	}
+
+	if !c.opts.NoGitOrigin {
+		if err := c.Run(c.opts.Dir, "git", "remote", "add", "origin", c.opts.GitOrigin); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// runGoGenerate runs go generate, which may download go modules, so it is retried as a network-bound step.
+func (c *creator) runGoGenerate() error {
+	var err error
+
+	c.debug, err = c.retry(stepGenerateSources, func(ctx context.Context) ([]byte, error) {
+		return c.executor.Run(ctx, c.opts.Dir, "go", "generate", "./...")
+	})
+
+	return err
+}
+
+// syncGitHub creates or updates the GitHub repository, so the extension can be discovered by its topics.
+func (c *creator) syncGitHub() error {
+	gh := &GitHub{BaseURL: c.opts.GitHubAPI, Token: c.opts.GitHubToken}
+
+	var err error
+
+	c.debug, err = c.retry(stepSyncGitHub, func(ctx context.Context) ([]byte, error) {
+		repo, created, err := gh.SyncRepository(ctx,
+			c.opts.RepoOwner, c.opts.RepoName, c.opts.Summary, c.opts.Kind.Topics())
+		if err != nil {
+			return nil, err
+		}
+
+		c.repoURL = repo.HTMLURL
+
+		action := "Updated"
+		if created {
+			action = "Created"
+		}
+
+		return []byte(fmt.Sprintf("%s repository %s\n", action, repo.HTMLURL)), nil
+	})
+
+	return err
+}
+
+// publish pushes the initial commit to the git origin, after the repository has been created by syncGitHub.
+func (c *creator) publish() error {
+	var err error
+
+	c.debug, err = c.retry(stepPublish, func(ctx context.Context) ([]byte, error) {
+		return c.executor.Run(ctx, c.opts.Dir, "git", "push", "--set-upstream", "origin", "HEAD")
+	})
+
+	return err
+}
+
+func (c *creator) needInstall() bool {
+	return !c.opts.NoInstall || len(c.opts.XK6Version) != 0
+}
+
+// install starts installing xk6 in the background.
+// It is independent of the other steps, its result is reported by the "Install xk6" step.
+func (c *creator) install() *task {
+	version := c.opts.XK6Version
+	if len(version) == 0 {
+		version = "latest"
+	}
+
+	return c.background(stepInstallXK6, "", "go", "install", "go.k6.io/xk6/cmd/xk6@"+version)
+}
+
+func (c *creator) verify() error {
+	checks := []struct {
+		msg  string
+		args []string
+	}{
+		{msg: stepVerifyGoBuild, args: []string{"build", "./..."}},
+		{msg: stepVerifyGoVet, args: []string{"vet", "./..."}},
+		{msg: stepVerifyGoTest, args: []string{"test", "./..."}},
+	}
+
+	for _, check := range checks {
+		args := check.args
+
+		if err := c.Step(check.msg, func() error { return c.Run(c.opts.Dir, "go", args...) }); err != nil {
+			return err
+		}
+	}
+
+	if !c.opts.VerifyK6 {
+		return nil
+	}
+
+	if err := c.Step(stepVerifyK6Build, c.buildK6); err != nil {
+		return err
+	}
+
+	if !c.opts.Smoke {
+		return nil
+	}
+
+	return c.Step(stepSmokeTest, c.smokeTest)
+}
+
+func (c *creator) buildK6() error {
+	dir, err := os.MkdirTemp("", "k6-")
+	if err != nil {
+		return err
+	}
+
+	// stored before the build, so the cleanup removes the directory even if the build fails
+	c.k6 = filepath.Join(dir, "k6")
+
+	return c.Run(c.opts.Dir, "xk6", "build", "--with", c.opts.GoModule+"=.", "--output", c.k6)
+}
+
+// smokeTest runs the generated test.js with the k6 binary built during verification.
+// In the case of an Output extension, a trivial script is run with the --out flag to prove registration.
+func (c *creator) smokeTest() error {
+	args := []string{"run", "--vus", "1", "--iterations", "1"}
+
+	if c.opts.Kind == JavaScript {
+		return c.Run(c.opts.Dir, c.k6, append(args, "test.js")...)
+	}
+
+	script := filepath.Join(filepath.Dir(c.k6), "smoke.js")
+
+	err := os.WriteFile(script, []byte("export default function () {}\n"), 0o600)
+	if err != nil {
+		return err
+	}
+
+	return c.Run(c.opts.Dir, c.k6, append(args, "--out", c.opts.Name, script)...)
+}
+
+// cleanup removes the temporary files (the downloaded template and the k6 binary built during verification).
+// If the creation has been canceled before the extension has been written and committed,
+// the partially created extension directory is also removed.
+func (c *creator) cleanup() {
+	if len(c.srcDir) != 0 {
+		_ = os.RemoveAll(c.srcDir)
+	}
+
+	if len(c.k6) != 0 {
+		_ = os.RemoveAll(filepath.Dir(c.k6))
+	}
+
+	if c.created && !c.written && c.ctx.Err() != nil {
+		_ = os.RemoveAll(c.opts.Dir)
+	}
+}
+
+func (c *creator) commitGitRepository() error {
+	if err := c.Run(c.opts.Dir, "git", "add", "."); err != nil {
+		return err
+	}
+
+	return c.Run(c.opts.Dir, "git", "commit", "-m", "Initial commit")
+}
+
+func (c *creator) create() (*Result, error) {
+	defer c.cleanup()
+
+	c.Begin("Creating extension")
+
+	var installing *task
+
+	if c.needInstall() {
+		installing = c.install()
+
+		// the install is canceled if a later step fails
+		defer installing.stop()
+	}
+
+	if err := c.Step(stepDownloadTemplate, c.downloadTemplate); err != nil {
+		return nil, err
+	}
+
+	if err := c.Step(stepCheckGoVersion, c.checkGoVersion); err != nil {
+		return nil, err
+	}
+
+	if err := c.Step(stepResolveK6Version, c.resolveK6Version); err != nil {
+		return nil, err
+	}
+
+	if err := c.Step(stepExpandTemplate, c.expandTemplate); err != nil {
+		return nil, err
+	}
+
+	if err := c.Step(stepWriteRegistryEntry, c.writeRegistryEntry); err != nil {
+		return nil, err
+	}
+
+	if c.pinK6 {
+		if err := c.Step(stepPinK6Version, c.pinK6Version); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(c.opts.CI) != 0 {
+		if err := c.Step(stepGenerateCI, c.generateCI); err != nil {
+			return nil, err
+		}
+	}
+
+	if !c.opts.NoGitInit {
+		if err := c.Step(stepCreateGitRepository, c.createGitRepository); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := c.Step(stepGenerateSources, c.runGoGenerate); err != nil {
+		return nil, err
+	}
+
+	if !c.opts.NoGitInit {
+		if err := c.Step(stepCommitGitRepository, c.commitGitRepository); err != nil {
+			return nil, err
+		}
+	}
+
+	c.written = true
+
+	if c.opts.SyncGitHub || c.opts.Publish {
+		if err := c.Step(stepSyncGitHub, c.syncGitHub); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.opts.Publish {
+		if err := c.Step(stepPublish, c.publish); err != nil {
+			return nil, err
+		}
+	}
+
+	if installing != nil {
+		if err := c.Step(stepInstallXK6, func() error { return c.wait(installing) }); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.opts.Verify {
+		if err := c.verify(); err != nil {
+			return nil, err
+		}
+	}
+
+	res := &Result{
+		Kind:         c.opts.Kind,
+		Name:         c.opts.Name,
+		Dir:          c.opts.Dir,
+		GoModule:     c.opts.GoModule,
+		GitOrigin:    c.opts.GitOrigin,
+		RepoURL:      c.repoURL,
+		BuildCommand: fmt.Sprintf("xk6 build --with %s=.", c.opts.GoModule),
+	}
+
+	c.emit(&Event{Type: EventResult, Result: res})
+
+	return res, nil
+}
+
+const k6Module = "go.k6.io/k6"
+
+// the network-bound steps, which are retried on failure
+const (
+	stepDownloadTemplate = "Download template"
+	stepResolveK6Version = "Resolve k6 version"
+	stepPinK6Version     = "Pin k6 version"
+	stepInstallXK6       = "Install xk6"
+	stepGenerateSources  = "Generate sources"
+	stepSyncGitHub       = "Sync GitHub repository"
+	stepPublish          = "Publish repository"
+)
+
+// the other steps
+const (
+	stepCheckGoVersion      = "Check go version"
+	stepExpandTemplate      = "Expand template"
+	stepWriteRegistryEntry  = "Write registry entry"
+	stepGenerateCI          = "Generate CI workflows"
+	stepCreateGitRepository = "Create git repository"
+	stepCommitGitRepository = "Commit git repository"
+	stepVerifyGoBuild       = "Verify go build"
+	stepVerifyGoVet         = "Verify go vet"
+	stepVerifyGoTest        = "Verify go test"
+	stepVerifyK6Build       = "Verify k6 build"
+	stepSmokeTest           = "Smoke test"
+)
+
+// steps returns the titles of all the steps run by Create.
+func steps() []string {
+	return []string{
+		stepDownloadTemplate, stepCheckGoVersion, stepResolveK6Version, stepExpandTemplate,
+		stepWriteRegistryEntry, stepPinK6Version, stepGenerateCI, stepCreateGitRepository,
+		stepGenerateSources, stepCommitGitRepository, stepSyncGitHub, stepPublish, stepInstallXK6,
+		stepVerifyGoBuild, stepVerifyGoVet, stepVerifyGoTest, stepVerifyK6Build, stepSmokeTest,
+	}
+}
+
+var reK6Require = regexp.MustCompile(`(?m)^\s*(?:require\s+)?go\.k6\.io/k6\s+(v\S+)`) //nolint:gochecknoglobals
+
+// ErrUnknownKind is returned for an extension type without a template.
+var ErrUnknownKind = errors.New("unknown extension type")
+
+// ErrOutdatedGo is returned if the go toolchain is older than required by the template.
+var ErrOutdatedGo = errors.New("outdated go toolchain")
@@ -0,0 +1,789 @@
+package scaffold
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/szkiba/create-k6-extension/scaffold/scaffoldtest"
+)
+
+const fakeTemplateModule = "github.com/szkiba/xk6-template-javascript"
+
+func fakeCreateExecutor(t *testing.T) *scaffoldtest.Executor {
+	t.Helper()
+
+	exec := scaffoldtest.NewExecutor()
+
+	exec.Outputs["^go list -m$"] = fakeTemplateModule + "\n"
+	exec.Outputs["^go list -m -f"] = "v0.49.0\n"
+	exec.Outputs["^go version$"] = "go version go1.21.5 linux/amd64\n"
+	exec.Hooks["^git clone"] = func(_ string, args []string) error {
+		dir := args[len(args)-1]
+
+		files := map[string]string{
+			"go.mod":         "module " + fakeTemplateModule + "\n\ngo 1.21\n\nrequire go.k6.io/k6 v0.48.0\n",
+			"ˮnameˮ.go":      "package ˮgoPackageˮ\n\n// ˮsummaryˮ\n",
+			".git/HEAD":      "ref: refs/heads/main\n",
+			"VERSION":        "k6 ˮk6Versionˮ\n",
+			"docs/ˮnameˮ.md": "# " + fakeTemplateModule + "\n",
+		}
+
+		for name, content := range files {
+			filename := filepath.Join(dir, name)
+
+			if err := os.MkdirAll(filepath.Dir(filename), 0o750); err != nil {
+				return err
+			}
+
+			if err := os.WriteFile(filename, []byte(content), 0o600); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return exec
+}
+
+// installLine is the command line of the xk6 install running in the background.
+const installLine = "go install go.k6.io/xk6/cmd/xk6@latest"
+
+// foreground returns the lines without the background xk6 install, which runs in any order.
+func foreground(lines []string) []string {
+	result := make([]string, 0, len(lines))
+
+	for _, line := range lines {
+		if !strings.HasPrefix(line, "go install go.k6.io/xk6/") {
+			result = append(result, line)
+		}
+	}
+
+	return result
+}
+
+// events records the events of the creation.
+type events []*Event
+
+func (e *events) record(event *Event) {
+	*e = append(*e, event)
+}
+
+// finished returns the finish event of the step, or nil if the step has not been finished.
+func (e events) finished(step string) *Event {
+	for _, event := range e {
+		if event.Type == EventFinish && event.Step == step {
+			return event
+		}
+	}
+
+	return nil
+}
+
+func fakeCreateOptions(t *testing.T) (*Options, *scaffoldtest.Executor) {
+	t.Helper()
+
+	exec := fakeCreateExecutor(t)
+
+	opts := &Options{
+		Kind:         JavaScript,
+		Name:         "foo",
+		Summary:      "Foo extension",
+		RepoOwner:    "acme",
+		RepoProtocol: "ssh",
+		Dir:          filepath.Join(t.TempDir(), "xk6-foo"),
+		Executor:     exec,
+	}
+
+	opts.Guess()
+	opts.Update()
+
+	return opts, exec
+}
+
+func Test_create(t *testing.T) {
+	t.Parallel()
+
+	opts, exec := fakeCreateOptions(t)
+
+	_, err := Create(context.Background(), opts)
+
+	require.NoError(t, err)
+
+	assert.Contains(t, exec.Lines(), installLine)
+
+	lines := foreground(exec.Lines())
+
+	require.Len(t, lines, 8)
+	assert.Regexp(t, "^git clone --depth 1 https://github.com/szkiba/xk6-template-javascript.git ", lines[0])
+	assert.Equal(t, []string{
+		"go version",
+		"go list -m",
+		"git init " + opts.Dir,
+		"git remote add origin git@github.com:acme/xk6-foo.git",
+		"go generate ./...",
+		"git add .",
+		"git commit -m Initial commit",
+	}, lines[1:])
+
+	content, err := os.ReadFile(filepath.Join(opts.Dir, "foo.go"))
+
+	require.NoError(t, err)
+	assert.Equal(t, "package foo\n\n// Foo extension\n", string(content))
+
+	content, err = os.ReadFile(filepath.Join(opts.Dir, "go.mod"))
+
+	require.NoError(t, err)
+	assert.Equal(t, "module github.com/acme/xk6-foo\n\ngo 1.21\n\nrequire go.k6.io/k6 v0.48.0\n", string(content))
+
+	content, err = os.ReadFile(filepath.Join(opts.Dir, "VERSION"))
+
+	require.NoError(t, err)
+	assert.Equal(t, "k6 v0.48.0\n", string(content))
+
+	assert.FileExists(t, filepath.Join(opts.Dir, "docs", "foo.md"))
+	assert.NoDirExists(t, filepath.Join(opts.Dir, ".git"))
+
+	entry, err := ReadRegistryEntry(opts.Dir)
+
+	require.NoError(t, err)
+	assert.Equal(t, opts.RegistryEntry(), entry)
+}
+
+func Test_create_outdatedGo(t *testing.T) {
+	t.Parallel()
+
+	opts, exec := fakeCreateOptions(t)
+
+	exec.Outputs["^go version$"] = "go version go1.20.14 linux/amd64\n"
+
+	_, err := Create(context.Background(), opts)
+
+	require.ErrorIs(t, err, ErrOutdatedGo)
+	assert.Contains(t, err.Error(), "go 1.21 or newer is required by the JavaScript template, found 1.20.14")
+	assert.NoDirExists(t, opts.Dir)
+	assert.NotContains(t, exec.Lines(), "go generate ./...")
+}
+
+func Test_create_options(t *testing.T) {
+	t.Parallel()
+
+	opts, exec := fakeCreateOptions(t)
+
+	opts.NoGitInit = true
+	opts.NoInstall = true
+
+	_, err := Create(context.Background(), opts)
+
+	require.NoError(t, err)
+
+	lines := exec.Lines()
+
+	require.Len(t, lines, 4)
+	assert.Equal(t, []string{"go version", "go list -m", "go generate ./..."}, lines[1:])
+}
+
+func Test_create_failure(t *testing.T) {
+	t.Parallel()
+
+	errFake := errors.New("fake failure")
+
+	patterns := []string{
+		"^git clone",
+		"^go version$",
+		"^go list -m$",
+		"^git init",
+		"^git remote add",
+		"^go generate",
+		"^git add",
+		"^git commit",
+	}
+
+	for idx, pattern := range patterns {
+		idx, pattern := idx, pattern
+
+		t.Run(pattern, func(t *testing.T) {
+			t.Parallel()
+
+			opts, exec := fakeCreateOptions(t)
+
+			exec.Failures[pattern] = errFake
+
+			_, err := Create(context.Background(), opts)
+
+			assert.ErrorIs(t, err, errFake)
+
+			var serr *StepError
+
+			require.ErrorAs(t, err, &serr)
+			assert.NotEmpty(t, serr.Step)
+
+			lines := foreground(exec.Lines())
+
+			require.Len(t, lines, idx+1)
+			assert.Regexp(t, pattern, lines[idx])
+		})
+	}
+}
+
+func Test_create_install_failure(t *testing.T) {
+	t.Parallel()
+
+	opts, exec := fakeCreateOptions(t)
+
+	errFake := errors.New("fake failure")
+
+	exec.Failures["^go install"] = errFake
+	exec.Outputs["^go install"] = "go: downloading go.k6.io/xk6 failed\n"
+
+	var recorded events
+
+	opts.OnEvent = recorded.record
+
+	_, err := Create(context.Background(), opts)
+
+	assert.ErrorIs(t, err, errFake)
+	assert.Len(t, foreground(exec.Lines()), 8)
+
+	var serr *StepError
+
+	require.ErrorAs(t, err, &serr)
+	assert.Equal(t, "Install xk6", serr.Step)
+	assert.Contains(t, string(serr.Output), "downloading go.k6.io/xk6 failed")
+
+	require.NotNil(t, recorded.finished("Commit git repository"))
+	require.NotNil(t, recorded.finished("Install xk6"))
+	assert.NoError(t, recorded.finished("Commit git repository").Err)
+	assert.ErrorIs(t, recorded.finished("Install xk6").Err, errFake)
+}
+
+// hangingInstall is an executor, where the xk6 install runs until it is canceled.
+type hangingInstall struct {
+	*scaffoldtest.Executor
+	stopped chan struct{}
+}
+
+func (h *hangingInstall) Run(ctx context.Context, dir string, name string, args ...string) ([]byte, error) {
+	if name != "go" || args[0] != "install" {
+		return h.Executor.Run(ctx, dir, name, args...)
+	}
+
+	<-ctx.Done()
+	close(h.stopped)
+
+	return nil, ctx.Err()
+}
+
+func Test_create_install_canceled(t *testing.T) {
+	t.Parallel()
+
+	opts, exec := fakeCreateOptions(t)
+
+	errFake := errors.New("fake failure")
+
+	exec.Failures["^go generate"] = errFake
+
+	install := &hangingInstall{Executor: exec, stopped: make(chan struct{})}
+
+	opts.Executor = install
+
+	_, err := Create(context.Background(), opts)
+
+	require.ErrorIs(t, err, errFake)
+
+	select {
+	case <-install.stopped:
+	default:
+		assert.Fail(t, "the xk6 install has not been stopped")
+	}
+}
+
+func Test_create_verify(t *testing.T) {
+	t.Parallel()
+
+	opts, exec := fakeCreateOptions(t)
+
+	opts.NoInstall = true
+	opts.Verify = true
+	opts.VerifyK6 = true
+
+	_, err := Create(context.Background(), opts)
+
+	require.NoError(t, err)
+
+	lines := exec.Lines()
+
+	require.Len(t, lines, 12)
+	assert.Equal(t, []string{"go build ./...", "go vet ./...", "go test ./..."}, lines[8:11])
+	assert.Regexp(t, "^xk6 build --with github.com/acme/xk6-foo=. --output .*k6$", lines[11])
+
+	for _, call := range exec.Calls()[8:] {
+		assert.Equal(t, opts.Dir, call.Dir)
+	}
+}
+
+func Test_create_verify_failure(t *testing.T) {
+	t.Parallel()
+
+	opts, exec := fakeCreateOptions(t)
+
+	opts.NoInstall = true
+	opts.Verify = true
+
+	errFake := errors.New("fake failure")
+
+	exec.Failures["^go vet"] = errFake
+	exec.Outputs["^go vet"] = "foo.go:1:1: vet: something is wrong\n"
+
+	var recorded events
+
+	opts.OnEvent = recorded.record
+
+	_, err := Create(context.Background(), opts)
+
+	assert.ErrorIs(t, err, errFake)
+
+	finished := recorded.finished("Verify go vet")
+
+	require.NotNil(t, finished)
+	assert.Contains(t, string(finished.Output), "vet: something is wrong")
+	assert.NotContains(t, exec.Lines(), "go test ./...")
+}
+
+func Test_create_verifyK6_failure(t *testing.T) {
+	t.Parallel()
+
+	opts, exec := fakeCreateOptions(t)
+
+	opts.NoInstall = true
+	opts.Verify = true
+	opts.VerifyK6 = true
+
+	errFake := errors.New("fake failure")
+
+	var output string
+
+	exec.Hooks["^xk6 build"] = func(_ string, args []string) error {
+		output = args[len(args)-1]
+
+		return errFake
+	}
+
+	_, err := Create(context.Background(), opts)
+
+	require.ErrorIs(t, err, errFake)
+	require.NotEmpty(t, output)
+	assert.NoDirExists(t, filepath.Dir(output))
+}
+
+func Test_create_smoke(t *testing.T) {
+	t.Parallel()
+
+	for _, k := range []Kind{JavaScript, Output} {
+		k := k
+
+		t.Run(string(k), func(t *testing.T) {
+			t.Parallel()
+
+			opts, exec := fakeCreateOptions(t)
+
+			opts.Kind = k
+			opts.RepoName = k.RepoNamePrefix() + opts.Name
+			opts.NoInstall = true
+			opts.Verify = true
+			opts.VerifyK6 = true
+			opts.Smoke = true
+
+			_, err := Create(context.Background(), opts)
+
+			require.NoError(t, err)
+
+			lines := exec.Lines()
+
+			require.Len(t, lines, 13)
+
+			if k == JavaScript {
+				assert.Regexp(t, "k6 run --vus 1 --iterations 1 test.js$", lines[12])
+			} else {
+				assert.Regexp(t, "k6 run --vus 1 --iterations 1 --out foo .*smoke.js$", lines[12])
+			}
+		})
+	}
+}
+
+func Test_create_versions(t *testing.T) {
+	t.Parallel()
+
+	opts, exec := fakeCreateOptions(t)
+
+	opts.NoInstall = true
+	opts.K6Version = "latest"
+	opts.XK6Version = "v0.10.0"
+
+	_, err := Create(context.Background(), opts)
+
+	require.NoError(t, err)
+
+	lines := exec.Lines()
+
+	assert.Contains(t, lines, "go install go.k6.io/xk6/cmd/xk6@v0.10.0")
+
+	lines = foreground(lines)
+
+	require.Len(t, lines, 10)
+	assert.Equal(t, "go list -m -f {{.Version}} go.k6.io/k6@latest", lines[2])
+	assert.Equal(t, "go get go.k6.io/k6@v0.49.0", lines[4])
+
+	content, err := os.ReadFile(filepath.Join(opts.Dir, "VERSION"))
+
+	require.NoError(t, err)
+	assert.Equal(t, "k6 v0.49.0\n", string(content))
+}
+
+func Test_create_events(t *testing.T) {
+	t.Parallel()
+
+	opts, _ := fakeCreateOptions(t)
+
+	opts.NoInstall = true
+
+	var recorded events
+
+	opts.OnEvent = recorded.record
+
+	res, err := Create(context.Background(), opts)
+
+	require.NoError(t, err)
+	require.NotEmpty(t, recorded)
+
+	assert.Equal(t, EventBegin, recorded[0].Type)
+	assert.Equal(t, EventStart, recorded[1].Type)
+	assert.Equal(t, "Download template", recorded[1].Step)
+
+	last := recorded[len(recorded)-1]
+
+	assert.Equal(t, EventResult, last.Type)
+	assert.Same(t, res, last.Result)
+	assert.Equal(t, "github.com/acme/xk6-foo", res.GoModule)
+	assert.Equal(t, "xk6 build --with github.com/acme/xk6-foo=.", res.BuildCommand)
+}
+
+func Test_create_canceled(t *testing.T) {
+	t.Parallel()
+
+	opts, exec := fakeCreateOptions(t)
+
+	opts.NoInstall = true
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	cancel()
+
+	_, err := Create(ctx, opts)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, exec.Lines())
+}
+
+func Test_create_unknown_kind(t *testing.T) {
+	t.Parallel()
+
+	opts, _ := fakeCreateOptions(t)
+
+	opts.Kind = "Unknown"
+	opts.NoInstall = true
+
+	_, err := Create(context.Background(), opts)
+
+	assert.ErrorIs(t, err, ErrUnknownKind)
+}
+
+func Test_create_interrupted(t *testing.T) {
+	t.Parallel()
+
+	opts, exec := fakeCreateOptions(t)
+
+	opts.NoInstall = true
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	exec.Hooks["^go generate"] = func(_ string, _ []string) error {
+		cancel()
+
+		return errors.New("signal: killed")
+	}
+
+	var recorded events
+
+	opts.OnEvent = recorded.record
+
+	_, err := Create(ctx, opts)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.NotContains(t, exec.Lines(), "git add .")
+	assert.NoDirExists(t, opts.Dir)
+
+	finished := recorded.finished("Generate sources")
+
+	require.NotNil(t, finished)
+	assert.ErrorIs(t, finished.Err, context.Canceled)
+}
+
+func Test_create_interrupted_afterCommit(t *testing.T) {
+	t.Parallel()
+
+	opts, exec := fakeCreateOptions(t)
+
+	opts.NoInstall = true
+	opts.Verify = true
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	exec.Hooks["^go test"] = func(_ string, _ []string) error {
+		cancel()
+
+		return errors.New("signal: killed")
+	}
+
+	_, err := Create(ctx, opts)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Contains(t, exec.Lines(), "git add .")
+
+	// the committed extension is kept
+	assert.DirExists(t, opts.Dir)
+	assert.FileExists(t, filepath.Join(opts.Dir, "go.mod"))
+}
+
+func Test_create_retry(t *testing.T) {
+	t.Parallel()
+
+	opts, exec := fakeCreateOptions(t)
+
+	opts.NoInstall = true
+	opts.Retries = 1
+	opts.RetryBackoff = time.Millisecond
+
+	failed := false
+
+	exec.Hooks["^go generate"] = func(_ string, _ []string) error {
+		if failed {
+			return nil
+		}
+
+		failed = true
+
+		return errors.New("go: downloading github.com/szkiba/tygor failed")
+	}
+
+	var recorded events
+
+	opts.OnEvent = recorded.record
+
+	_, err := Create(context.Background(), opts)
+
+	require.NoError(t, err)
+
+	finished := recorded.finished("Generate sources")
+
+	require.NotNil(t, finished)
+	assert.Contains(t, string(finished.Output), "attempt 1 of 2 failed")
+	assert.Len(t, foreground(exec.Lines()), 9)
+}
+
+func Test_create_versions_retry(t *testing.T) {
+	t.Parallel()
+
+	opts, exec := fakeCreateOptions(t)
+
+	opts.NoInstall = true
+	opts.K6Version = "latest"
+	opts.Retries = 1
+	opts.RetryBackoff = time.Millisecond
+
+	failed := map[string]bool{}
+
+	for _, pattern := range []string{"^go list -m -f", "^go get"} {
+		pattern := pattern
+
+		exec.Hooks[pattern] = func(_ string, _ []string) error {
+			if failed[pattern] {
+				return nil
+			}
+
+			failed[pattern] = true
+
+			return errors.New("proxy.golang.org: connection reset by peer")
+		}
+	}
+
+	var recorded events
+
+	opts.OnEvent = recorded.record
+
+	_, err := Create(context.Background(), opts)
+
+	require.NoError(t, err)
+
+	var retried []string
+
+	for _, e := range recorded {
+		if e.Type == EventRetry {
+			retried = append(retried, e.Step)
+		}
+	}
+
+	assert.Equal(t, []string{stepResolveK6Version, stepPinK6Version}, retried)
+	assert.Equal(t, "v0.49.0", opts.K6Version)
+}
+
+func Test_create_install_retry(t *testing.T) {
+	t.Parallel()
+
+	opts, exec := fakeCreateOptions(t)
+
+	opts.Retries = 1
+	opts.RetryBackoff = time.Millisecond
+
+	var failed atomic.Bool
+
+	exec.Hooks["^go install"] = func(_ string, _ []string) error {
+		if failed.Swap(true) {
+			return nil
+		}
+
+		return errors.New("go: downloading go.k6.io/xk6 failed")
+	}
+
+	var recorded events
+
+	opts.OnEvent = recorded.record
+
+	_, err := Create(context.Background(), opts)
+
+	require.NoError(t, err)
+
+	var steps []string
+
+	for _, e := range recorded {
+		if e.Type == EventRetry {
+			require.Equal(t, stepInstallXK6, e.Step)
+			assert.Equal(t, 1, e.Attempt)
+			assert.Equal(t, 2, e.Attempts)
+		}
+
+		if e.Step == stepInstallXK6 {
+			steps = append(steps, string(e.Type))
+		}
+	}
+
+	// the retry of the background install is reported within its own step
+	assert.Equal(t, []string{"start", "retry", "finish"}, steps)
+}
+
+func Test_create_sync_github(t *testing.T) {
+	t.Parallel()
+
+	srv := scaffoldtest.NewGitHub("acme", "secret")
+
+	defer srv.Close()
+
+	opts, _ := fakeCreateOptions(t)
+
+	opts.NoInstall = true
+	opts.SyncGitHub = true
+	opts.GitHubAPI = srv.URL
+	opts.GitHubToken = "secret"
+
+	var recorded events
+
+	opts.OnEvent = recorded.record
+
+	_, err := Create(context.Background(), opts)
+
+	require.NoError(t, err)
+
+	repo := srv.Repository("acme/xk6-foo")
+
+	require.NotNil(t, repo)
+	assert.Equal(t, "Foo extension", repo.Description)
+	assert.Equal(t, []string{"xk6", "k6-extension", "xk6-javascript"}, repo.Topics)
+
+	finished := recorded.finished("Sync GitHub repository")
+
+	require.NotNil(t, finished)
+	assert.Equal(t, "Created repository https://github.com/acme/xk6-foo\n", string(finished.Output))
+
+	opts.GitHubToken = ""
+
+	assert.ErrorIs(t, opts.Validate(), ErrInvalidOption)
+}
+
+func Test_create_publish(t *testing.T) {
+	t.Parallel()
+
+	srv := scaffoldtest.NewGitHub("jdoe", "secret")
+
+	defer srv.Close()
+
+	opts, exec := fakeCreateOptions(t)
+
+	opts.NoInstall = true
+	opts.Publish = true
+	opts.GitHubAPI = srv.URL
+	opts.GitHubToken = "secret"
+
+	var recorded events
+
+	opts.OnEvent = recorded.record
+
+	res, err := Create(context.Background(), opts)
+
+	require.NoError(t, err)
+
+	assert.NotNil(t, srv.Repository("acme/xk6-foo"))
+	assert.Contains(t, srv.Requests(), "POST /orgs/acme/repos")
+	assert.Equal(t, "https://github.com/acme/xk6-foo", res.RepoURL)
+
+	lines := exec.Lines()
+
+	assert.Equal(t, "git push --set-upstream origin HEAD", lines[len(lines)-1])
+	assert.Equal(t, opts.Dir, exec.Calls()[len(lines)-1].Dir)
+
+	require.NotNil(t, recorded.finished("Sync GitHub repository"))
+	require.NotNil(t, recorded.finished("Publish repository"))
+
+	opts.NoGitOrigin = true
+
+	assert.ErrorIs(t, opts.Validate(), ErrInvalidOption)
+}
+
+func Test_create_ci(t *testing.T) {
+	t.Parallel()
+
+	opts, _ := fakeCreateOptions(t)
+
+	opts.NoInstall = true
+	opts.CI = CIGitHub
+
+	_, err := Create(context.Background(), opts)
+
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(filepath.Join(opts.Dir, ".github", "workflows", "ci.yml"))
+
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "K6_VERSION: v0.48.0")
+	assert.Contains(t, string(content), "--with github.com/acme/xk6-foo=.")
+	assert.FileExists(t, filepath.Join(opts.Dir, ".github", "workflows", "release.yml"))
+}
@@ -0,0 +1,73 @@
+package scaffold
+
+import (
+	"fmt"
+	"time"
+)
+
+// EventType is the type of an Event.
+type EventType string
+
+const (
+	// EventBegin is emitted at the start of a series of steps.
+	EventBegin EventType = "begin"
+	// EventStart is emitted at the start of a step.
+	EventStart EventType = "start"
+	// EventFinish is emitted at the end of a step.
+	EventFinish EventType = "finish"
+	// EventRetry is emitted when a failed attempt of a network-bound step is going to be retried.
+	EventRetry EventType = "retry"
+	// EventResult is emitted after a successful creation.
+	EventResult EventType = "result"
+)
+
+// Event reports the progress or the result of the creation.
+type Event struct {
+	Type EventType
+	Time time.Time
+
+	// Title is the title of the series of steps (EventBegin).
+	Title string
+	// Step is the name of the step (EventStart, EventFinish, EventRetry).
+	Step string
+	// Duration is the duration of the step (EventFinish) or the delay before the next attempt (EventRetry).
+	Duration time.Duration
+	// Attempt is the number of the failed attempt and Attempts is the maximum number of attempts (EventRetry).
+	Attempt, Attempts int
+	// Output is the captured output of the command executed by the step (EventFinish).
+	Output []byte
+	// Err is the error of the failed step (EventFinish) or attempt (EventRetry).
+	Err error
+	// Result is the result of the creation (EventResult).
+	Result *Result
+}
+
+// Result is the outcome of a successful creation.
+type Result struct {
+	Kind      Kind   `json:"kind"`
+	Name      string `json:"name"`
+	Dir       string `json:"dir"`
+	GoModule  string `json:"goModule"`
+	GitOrigin string `json:"gitOrigin,omitempty"`
+	// RepoURL is the web URL of the GitHub repository, if it has been created or updated.
+	RepoURL      string `json:"repoURL,omitempty"`
+	BuildCommand string `json:"buildCommand"`
+}
+
+// StepError is returned when a step fails.
+type StepError struct {
+	// Step is the name of the failed step.
+	Step string
+	// Output is the captured output of the command executed by the step.
+	Output []byte
+	// Err is the cause of the failure.
+	Err error
+}
+
+func (e *StepError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Step, e.Err)
+}
+
+func (e *StepError) Unwrap() error {
+	return e.Err
+}
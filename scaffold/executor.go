@@ -0,0 +1,41 @@
+package scaffold
+
+import (
+	"context"
+	"os/exec"
+)
+
+// Executor runs external commands.
+// The dir parameter is the working directory of the command, empty means the current directory.
+// The command is killed if the context is done before the command completes.
+type Executor interface {
+	// Run returns the combined standard output and standard error of the command.
+	Run(ctx context.Context, dir string, name string, args ...string) ([]byte, error)
+	// Output returns the standard output of the command.
+	Output(ctx context.Context, dir string, name string, args ...string) ([]byte, error)
+}
+
+// NewExecutor returns an Executor that runs the commands as child processes.
+func NewExecutor() Executor {
+	return new(cmdExecutor)
+}
+
+type cmdExecutor struct{}
+
+func (*cmdExecutor) command(ctx context.Context, dir string, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+
+	if len(dir) != 0 {
+		cmd.Dir = dir
+	}
+
+	return cmd
+}
+
+func (e *cmdExecutor) Run(ctx context.Context, dir string, name string, args ...string) ([]byte, error) {
+	return e.command(ctx, dir, name, args...).CombinedOutput()
+}
+
+func (e *cmdExecutor) Output(ctx context.Context, dir string, name string, args ...string) ([]byte, error) {
+	return e.command(ctx, dir, name, args...).Output()
+}
@@ -0,0 +1,155 @@
+package scaffold
+
+import (
+	"context"
+	"flag"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/szkiba/create-k6-extension/scaffold/scaffoldtest"
+)
+
+var update = flag.Bool("update", false, "update golden files") //nolint:gochecknoglobals
+
+// copyDir copies the content of the src directory into the dst directory.
+func copyDir(t *testing.T, src, dst string) {
+	t.Helper()
+
+	err := filepath.WalkDir(src, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(dst, rel)
+
+		if entry.IsDir() {
+			return os.MkdirAll(target, 0o750)
+		}
+
+		content, err := os.ReadFile(filepath.Clean(path))
+		if err != nil {
+			return err
+		}
+
+		return os.WriteFile(target, content, 0o600)
+	})
+
+	require.NoError(t, err)
+}
+
+// readDir returns the content of the files in the directory indexed by relative path.
+func readDir(t *testing.T, dir string) map[string]string {
+	t.Helper()
+
+	files := make(map[string]string)
+
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		content, err := os.ReadFile(filepath.Clean(path))
+		if err != nil {
+			return err
+		}
+
+		files[filepath.ToSlash(rel)] = string(content)
+
+		return nil
+	})
+
+	require.NoError(t, err)
+
+	return files
+}
+
+// assertGolden compares the files (indexed by relative path) with the content of the golden directory.
+// With the -update flag, the golden directory is replaced by the files first.
+func assertGolden(t *testing.T, golden string, actual map[string]string) {
+	t.Helper()
+
+	if *update {
+		require.NoError(t, os.RemoveAll(golden))
+
+		for rel, content := range actual {
+			filename := filepath.Join(golden, filepath.FromSlash(rel))
+
+			require.NoError(t, os.MkdirAll(filepath.Dir(filename), 0o750))
+			require.NoError(t, os.WriteFile(filename, []byte(content), 0o600))
+		}
+	}
+
+	assert.Equal(t, readDir(t, golden), actual)
+}
+
+func expandFixture(t *testing.T, opts *Options) string {
+	t.Helper()
+
+	exec := scaffoldtest.NewExecutor()
+	exec.Outputs["^go list -m$"] = fakeTemplateModule + "\n"
+
+	opts.Executor = exec
+
+	c, err := newCreator(context.Background(), opts)
+	require.NoError(t, err)
+
+	c.srcDir = t.TempDir()
+	copyDir(t, filepath.Join("testdata", "template"), c.srcDir)
+
+	opts.Dir = filepath.Join(t.TempDir(), opts.Dir)
+
+	require.NoError(t, c.expandTemplate())
+	assert.NoDirExists(t, c.srcDir)
+
+	return opts.Dir
+}
+
+func Test_creator_expandTemplate(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]*Options{
+		"javascript": {
+			Kind:         JavaScript,
+			Name:         "foo",
+			Summary:      "Foo extension for k6",
+			RepoOwner:    "acme",
+			RepoProtocol: "ssh",
+		},
+		"output": {
+			Kind:     Output,
+			Name:     "fancy_bar",
+			Summary:  "Send metrics to fancy bar",
+			GoModule: "example.com/team/xk6-output-fancy_bar",
+		},
+	}
+
+	for name, opts := range tests {
+		name, opts := name, opts
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			opts.Guess()
+			opts.Update()
+
+			actual := readDir(t, expandFixture(t, opts))
+			golden := filepath.Join("testdata", "golden", name)
+
+			assertGolden(t, golden, actual)
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package scaffold
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// GitHub is a minimal client of the GitHub REST API, used to create and update the repository of the extension.
+type GitHub struct {
+	// BaseURL is the REST API base URL (default: https://api.github.com).
+	BaseURL string
+	// Token is the access token used for authentication.
+	Token string
+	// Client sends the requests (default: http.DefaultClient).
+	Client *http.Client
+}
+
+// Repository contains the used fields of a GitHub repository.
+type Repository struct {
+	FullName    string   `json:"full_name"`
+	HTMLURL     string   `json:"html_url"`
+	Description string   `json:"description"`
+	Topics      []string `json:"topics"`
+}
+
+// GitHubError is returned for a failed GitHub REST API request.
+type GitHubError struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Message    string
+}
+
+func (e *GitHubError) Error() string {
+	msg := fmt.Sprintf("%s %s: %d %s", e.Method, e.URL, e.StatusCode, http.StatusText(e.StatusCode))
+
+	if len(e.Message) != 0 {
+		msg += ": " + e.Message
+	}
+
+	return msg
+}
+
+// SyncRepository creates the repository if it does not exist yet, and sets its description and topics.
+// The repository is created for the authenticated user, or for the organization if the owner is not the user.
+// The returned flag is true if the repository has been created.
+func (gh *GitHub) SyncRepository(
+	ctx context.Context,
+	owner, name, description string,
+	topics []string,
+) (*Repository, bool, error) {
+	repo := new(Repository)
+	path := "/repos/" + owner + "/" + name
+	created := false
+
+	err := gh.do(ctx, http.MethodGet, path, nil, repo)
+
+	var gherr *GitHubError
+
+	switch {
+	case errors.As(err, &gherr) && gherr.StatusCode == http.StatusNotFound:
+		if repo, err = gh.createRepository(ctx, owner, name, description); err != nil {
+			return nil, false, err
+		}
+
+		created = true
+	case err != nil:
+		return nil, false, err
+	case repo.Description != description:
+		if err = gh.do(ctx, http.MethodPatch, path, map[string]string{"description": description}, repo); err != nil {
+			return nil, false, err
+		}
+	}
+
+	var names struct {
+		Names []string `json:"names"`
+	}
+
+	if err = gh.do(ctx, http.MethodPut, path+"/topics", map[string][]string{"names": topics}, &names); err != nil {
+		return nil, false, err
+	}
+
+	repo.Topics = names.Names
+
+	return repo, created, nil
+}
+
+func (gh *GitHub) createRepository(ctx context.Context, owner, name, description string) (*Repository, error) {
+	var user struct {
+		Login string `json:"login"`
+	}
+
+	if err := gh.do(ctx, http.MethodGet, "/user", nil, &user); err != nil {
+		return nil, err
+	}
+
+	path := "/orgs/" + owner + "/repos"
+	if strings.EqualFold(user.Login, owner) {
+		path = "/user/repos"
+	}
+
+	repo := new(Repository)
+	body := map[string]string{"name": name, "description": description}
+
+	if err := gh.do(ctx, http.MethodPost, path, body, repo); err != nil {
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+func (gh *GitHub) do(ctx context.Context, method, path string, in, out interface{}) error {
+	var body io.Reader
+
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+
+		body = bytes.NewReader(data)
+	}
+
+	base := gh.BaseURL
+	if len(base) == 0 {
+		base = defaultGitHubAPI
+	}
+
+	url := strings.TrimSuffix(base, "/") + path
+
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+
+	if len(gh.Token) != 0 {
+		req.Header.Set("Authorization", "Bearer "+gh.Token)
+	}
+
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	client := gh.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close() //nolint:errcheck
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var msg struct {
+			Message string `json:"message"`
+		}
+
+		_ = json.Unmarshal(data, &msg)
+
+		return &GitHubError{Method: method, URL: url, StatusCode: resp.StatusCode, Message: msg.Message}
+	}
+
+	return json.Unmarshal(data, out)
+}
+
+// Topics returns the GitHub topics used to discover the extensions of the given type.
+func (k Kind) Topics() []string {
+	if k == JavaScript {
+		return []string{"xk6", "k6-extension", "xk6-javascript"}
+	}
+
+	return []string{"xk6", "k6-extension", "xk6-output"}
+}
+
+const defaultGitHubAPI = "https://api.github.com"
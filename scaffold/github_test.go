@@ -0,0 +1,72 @@
+package scaffold
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/szkiba/create-k6-extension/scaffold/scaffoldtest"
+)
+
+func Test_GitHub_SyncRepository(t *testing.T) {
+	t.Parallel()
+
+	srv := scaffoldtest.NewGitHub("jdoe", "secret")
+
+	defer srv.Close()
+
+	srv.AddRepository("acme/xk6-bar", "old description", "xk6")
+
+	gh := &GitHub{BaseURL: srv.URL, Token: "secret"}
+	ctx := context.Background()
+
+	tests := []struct {
+		owner   string
+		name    string
+		created bool
+	}{
+		{owner: "jdoe", name: "xk6-foo", created: true},
+		{owner: "acme", name: "xk6-foo", created: true},
+		{owner: "acme", name: "xk6-bar", created: false},
+	}
+
+	for _, tt := range tests {
+		repo, created, err := gh.SyncRepository(ctx, tt.owner, tt.name, "Foo extension", JavaScript.Topics())
+
+		require.NoError(t, err)
+		assert.Equal(t, tt.created, created)
+		assert.Equal(t, tt.owner+"/"+tt.name, repo.FullName)
+		assert.Equal(t, "Foo extension", repo.Description)
+		assert.Equal(t, []string{"xk6", "k6-extension", "xk6-javascript"}, repo.Topics)
+
+		stored := srv.Repository(tt.owner + "/" + tt.name)
+
+		require.NotNil(t, stored)
+		assert.Equal(t, "Foo extension", stored.Description)
+		assert.Equal(t, JavaScript.Topics(), stored.Topics)
+	}
+
+	assert.Contains(t, srv.Requests(), "POST /user/repos")
+	assert.Contains(t, srv.Requests(), "POST /orgs/acme/repos")
+	assert.Contains(t, srv.Requests(), "PATCH /repos/acme/xk6-bar")
+}
+
+func Test_GitHub_SyncRepository_error(t *testing.T) {
+	t.Parallel()
+
+	srv := scaffoldtest.NewGitHub("jdoe", "secret")
+
+	defer srv.Close()
+
+	gh := &GitHub{BaseURL: srv.URL, Token: "wrong"}
+
+	_, _, err := gh.SyncRepository(context.Background(), "jdoe", "xk6-foo", "", Output.Topics())
+
+	var gherr *GitHubError
+
+	require.ErrorAs(t, err, &gherr)
+	assert.Equal(t, http.StatusUnauthorized, gherr.StatusCode)
+	assert.Equal(t, "Bad credentials", gherr.Message)
+}
@@ -0,0 +1,160 @@
+package scaffold
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Host is a git repository hosting service.
+// It derives the git origin URL and the go module path of the repository.
+type Host struct {
+	// Provider is the type of the hosting service.
+	Provider Provider
+	// Domain is the host name of the service (like gitlab.com or a self-managed git.example.com).
+	Domain string
+}
+
+// Provider is the type of a git repository hosting service.
+type Provider string
+
+const (
+	// ProviderGitHub is GitHub (github.com or GitHub Enterprise Server).
+	ProviderGitHub Provider = "github"
+	// ProviderGitLab is GitLab (gitlab.com or self-managed).
+	ProviderGitLab Provider = "gitlab"
+	// ProviderGitea is Gitea (gitea.com, codeberg.org or self-hosted).
+	ProviderGitea Provider = "gitea"
+	// ProviderBitbucket is Bitbucket Cloud (bitbucket.org).
+	ProviderBitbucket Provider = "bitbucket"
+)
+
+type provider struct {
+	title  string
+	domain string
+	// nested is true if the owner can be a nested group path (like org/team/subteam)
+	nested bool
+}
+
+//nolint:gochecknoglobals
+var providers = map[Provider]provider{
+	ProviderGitHub:    {title: "GitHub", domain: "github.com"},
+	ProviderGitLab:    {title: "GitLab", domain: "gitlab.com", nested: true},
+	ProviderGitea:     {title: "Gitea", domain: "gitea.com"},
+	ProviderBitbucket: {title: "Bitbucket", domain: "bitbucket.org"},
+}
+
+// wellKnownDomains contains the public hosting services without the provider in the domain name.
+//
+//nolint:gochecknoglobals
+var wellKnownDomains = map[string]Provider{
+	"codeberg.org": ProviderGitea,
+}
+
+// ParseHost parses the hosting service from a host name (like github.com or gitlab.example.com)
+// or a provider name (like gitlab). The provider of a self-managed service is guessed from the host name,
+// if it is not contained by the host name, it should be prefixed with the provider (like gitlab:git.example.com).
+// An empty value means github.com.
+func ParseHost(value string) (*Host, error) {
+	if len(value) == 0 {
+		return &Host{Provider: ProviderGitHub, Domain: providers[ProviderGitHub].domain}, nil
+	}
+
+	if name, domain, found := strings.Cut(value, ":"); found {
+		if _, known := providers[Provider(name)]; !known || !reDomain.MatchString(domain) {
+			return nil, errInvalidHost
+		}
+
+		return &Host{Provider: Provider(name), Domain: domain}, nil
+	}
+
+	if p, known := providers[Provider(value)]; known {
+		return &Host{Provider: Provider(value), Domain: p.domain}, nil
+	}
+
+	if !reDomain.MatchString(value) {
+		return nil, errInvalidHost
+	}
+
+	if prov, known := wellKnownDomains[value]; known {
+		return &Host{Provider: prov, Domain: value}, nil
+	}
+
+	for prov := range providers {
+		for _, label := range strings.Split(value, ".") {
+			if label == string(prov) {
+				return &Host{Provider: prov, Domain: value}, nil
+			}
+		}
+	}
+
+	return nil, errInvalidHost
+}
+
+// CheckRepoHost checks that the hosting service can be parsed by ParseHost.
+func CheckRepoHost(value string) error {
+	_, err := ParseHost(value)
+
+	return err
+}
+
+// CheckRepoOwner checks the repository owner: a user, organization or group name,
+// or a nested group path (like org/team/subteam) if it is supported by the hosting service.
+func CheckRepoOwner(h *Host, owner string) error {
+	if err := required(owner); err != nil {
+		return err
+	}
+
+	segments := strings.Split(owner, "/")
+
+	if len(segments) > 1 && !h.NestedOwner() {
+		return fmt.Errorf("%w %s", errNestedOwner, h.Title())
+	}
+
+	for _, segment := range segments {
+		if !reOwner.MatchString(segment) || strings.HasSuffix(segment, ".git") {
+			return errInvalidOwner
+		}
+	}
+
+	return nil
+}
+
+// NestedOwner returns true if the owner can be a nested group path (like org/team/subteam).
+func (h *Host) NestedOwner() bool {
+	return providers[h.Provider].nested
+}
+
+// Title returns the display name of the hosting service (like GitLab).
+func (h *Host) Title() string {
+	return providers[h.Provider].title
+}
+
+// GitOrigin returns the git origin URL of the repository with the given protocol (ssh or https).
+func (h *Host) GitOrigin(protocol, owner, name string) string {
+	if protocol == "ssh" {
+		return fmt.Sprintf("git@%s:%s/%s.git", h.Domain, owner, name)
+	}
+
+	return fmt.Sprintf("https://%s/%s/%s.git", h.Domain, owner, name)
+}
+
+// GoModule returns the go module path of the repository.
+func (h *Host) GoModule(owner, name string) string {
+	return path.Join(h.Domain, owner, name)
+}
+
+var (
+	reDomain = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?(\.[a-z0-9]([a-z0-9-]*[a-z0-9])?)+$`) //nolint:gochecknoglobals
+	reOwner  = regexp.MustCompile(`^[A-Za-z0-9_]([A-Za-z0-9_.-]*[A-Za-z0-9_-])?$`)                     //nolint:gochecknoglobals
+)
+
+var (
+	errInvalidHost = errors.New(
+		"github, gitlab, gitea or bitbucket host is required (prefix self-managed hosts like gitlab:git.example.com)",
+	)
+	errInvalidOwner = errors.New("alphanumeric characters, underscores, dashes and dots are required")
+	errNestedOwner  = errors.New("nested group paths are not supported by")
+)
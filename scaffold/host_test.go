@@ -0,0 +1,108 @@
+package scaffold
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseHost(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]*Host{
+		"":                       {Provider: ProviderGitHub, Domain: "github.com"},
+		"github.com":             {Provider: ProviderGitHub, Domain: "github.com"},
+		"gitlab":                 {Provider: ProviderGitLab, Domain: "gitlab.com"},
+		"gitlab.com":             {Provider: ProviderGitLab, Domain: "gitlab.com"},
+		"gitlab.example.com":     {Provider: ProviderGitLab, Domain: "gitlab.example.com"},
+		"gitlab:git.example.com": {Provider: ProviderGitLab, Domain: "git.example.com"},
+		"codeberg.org":           {Provider: ProviderGitea, Domain: "codeberg.org"},
+		"gitea:git.example.com":  {Provider: ProviderGitea, Domain: "git.example.com"},
+		"bitbucket.org":          {Provider: ProviderBitbucket, Domain: "bitbucket.org"},
+		"github.example.com":     {Provider: ProviderGitHub, Domain: "github.example.com"},
+	}
+
+	for value, expected := range tests {
+		host, err := ParseHost(value)
+
+		require.NoError(t, err, value)
+		assert.Equal(t, expected, host, value)
+	}
+
+	for _, value := range []string{"example.com", "svn:example.com", "gitlab:", "gitlab:Example.com", "https://gitlab.com"} {
+		_, err := ParseHost(value)
+
+		assert.ErrorIs(t, err, errInvalidHost, value)
+	}
+}
+
+func Test_Host(t *testing.T) {
+	t.Parallel()
+
+	host := &Host{Provider: ProviderGitLab, Domain: "git.example.com"}
+
+	assert.Equal(t, "GitLab", host.Title())
+	assert.Equal(t, "git@git.example.com:acme/xk6-foo.git", host.GitOrigin("ssh", "acme", "xk6-foo"))
+	assert.Equal(t, "https://git.example.com/acme/xk6-foo.git", host.GitOrigin("https", "acme", "xk6-foo"))
+	assert.Equal(t, "git.example.com/acme/xk6-foo", host.GoModule("acme", "xk6-foo"))
+}
+
+func Test_Options_Validate_host(t *testing.T) {
+	t.Parallel()
+
+	opts := &Options{Kind: JavaScript, Name: "foo", RepoHost: "bitbucket.org", RepoOwner: "acme", RepoProtocol: "ssh"}
+
+	opts.Guess()
+
+	require.NoError(t, opts.Validate())
+	assert.Equal(t, "bitbucket.org/acme/xk6-foo", opts.GoModule)
+	assert.Equal(t, "git@bitbucket.org:acme/xk6-foo.git", opts.GitOrigin)
+
+	opts.SyncGitHub = true
+	opts.GitHubToken = "secret"
+
+	assert.ErrorIs(t, opts.Validate(), errGitHubRequired)
+
+	opts.SyncGitHub = false
+	opts.RepoHost = "example.com"
+
+	assert.ErrorIs(t, opts.Validate(), errInvalidHost)
+}
+
+func Test_CheckRepoOwner(t *testing.T) {
+	t.Parallel()
+
+	github := &Host{Provider: ProviderGitHub, Domain: "github.com"}
+	gitlab := &Host{Provider: ProviderGitLab, Domain: "gitlab.com"}
+
+	for _, owner := range []string{"acme", "Acme-Corp", "acme.io", "jdoe_2"} {
+		assert.NoError(t, CheckRepoOwner(github, owner), owner)
+		assert.NoError(t, CheckRepoOwner(gitlab, owner), owner)
+	}
+
+	assert.NoError(t, CheckRepoOwner(gitlab, "acme/k6/team"))
+	assert.ErrorIs(t, CheckRepoOwner(github, "acme/k6/team"), errNestedOwner)
+
+	for _, owner := range []string{"", "acme/", "/acme", "acme//team", "acme team", "-acme", "acme.", "acme/team.git"} {
+		assert.Error(t, CheckRepoOwner(gitlab, owner), owner)
+	}
+}
+
+func Test_Options_Guess_nested(t *testing.T) {
+	t.Parallel()
+
+	opts := &Options{
+		Kind:         Output,
+		Name:         "foo",
+		RepoHost:     "gitlab.example.com",
+		RepoOwner:    "acme/k6/team",
+		RepoProtocol: "ssh",
+	}
+
+	opts.Guess()
+
+	require.NoError(t, opts.Validate())
+	assert.Equal(t, "gitlab.example.com/acme/k6/team/xk6-output-foo", opts.GoModule)
+	assert.Equal(t, "git@gitlab.example.com:acme/k6/team/xk6-output-foo.git", opts.GitOrigin)
+}
@@ -0,0 +1,267 @@
+package scaffold
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/iancoleman/strcase"
+)
+
+// Options contains the parameters of the extension to create.
+// The exported fields with JSON names are also available as template variables.
+// Missing values can be derived from the given ones with the Guess method.
+// UseGitHub enables hosting the repository on the RepoHost hosting service (as parsed by ParseHost,
+// default: github.com), the name is kept for the compatibility of the useGitHub template variable.
+type Options struct {
+	Dir string `json:"dir,omitempty"`
+
+	Kind         Kind   `json:"kind,omitempty"`
+	Name         string `json:"name,omitempty"`
+	Summary      string `json:"summary,omitempty"`
+	GitOrigin    string `json:"gitOrigin,omitempty"`
+	UseGitHub    bool   `json:"useGitHub,omitempty"`
+	RepoHost     string `json:"repoHost,omitempty"`
+	RepoOwner    string `json:"repoOwner,omitempty"`
+	RepoName     string `json:"repoName,omitempty"`
+	RepoProtocol string `json:"repoProtocol,omitempty"`
+	GoModule     string `json:"goModule,omitempty"`
+	GoPackage    string `json:"goPackage,omitempty"`
+
+	NoGitInit   bool `json:"noGitInit,omitempty"`
+	NoGitOrigin bool `json:"noGitOrigin,omitempty"`
+	NoAsk       bool `json:"noAsk,omitempty"`
+
+	PrimaryClass string `json:"PrimaryClass,omitempty"`
+	EnvPrefix    string `json:"envPrefix,omitempty"`
+
+	// Categories contains the k6 extension registry categories of the extension.
+	Categories []string `json:"categories,omitempty"`
+
+	K6Version  string `json:"k6Version,omitempty"`
+	XK6Version string `json:"xk6Version,omitempty"`
+
+	// NoInstall disables installing xk6 (unless XK6Version is set).
+	NoInstall bool `json:"-"`
+	// Verify enables verifying the created extension with go build, go vet and go test.
+	Verify bool `json:"-"`
+	// VerifyK6 enables verifying that k6 can be built with the extension.
+	VerifyK6 bool `json:"-"`
+	// Smoke enables running a smoke test with k6 built with the extension.
+	Smoke bool `json:"-"`
+
+	// Timeout limits the duration of the commands run by the steps, zero means no limit.
+	Timeout time.Duration `json:"-"`
+	// StepTimeouts overrides Timeout for the steps, indexed by step name (like "Install xk6" or "install-xk6").
+	// The names are compared by StepKey, unknown steps are reported by Validate.
+	StepTimeouts map[string]time.Duration `json:"-"`
+	// Retries is the number of retries of the failed network-bound steps (download template, resolve and pin
+	// k6 version, install xk6, generate sources, sync GitHub repository and publish repository).
+	Retries int `json:"-"`
+	// RetryBackoff is the delay before the first retry, doubled before each further retry (default: 1s).
+	RetryBackoff time.Duration `json:"-"`
+
+	// SyncGitHub enables creating or updating the GitHub repository through the REST API,
+	// setting its description (Summary) and topics (Kind.Topics).
+	SyncGitHub bool `json:"-"`
+	// Publish enables creating the GitHub repository (implies SyncGitHub)
+	// and pushing the initial commit to the git origin.
+	Publish bool `json:"-"`
+	// GitHubToken is the access token used by SyncGitHub and Publish.
+	GitHubToken string `json:"-"`
+	// GitHubAPI is the GitHub REST API base URL (default: https://api.github.com).
+	GitHubAPI string `json:"-"`
+
+	// CI selects the platform of the generated CI workflows (CIGitHub, CIGitLab or CIAuto).
+	// Without CI, the CI workflows of the template are kept.
+	CI string `json:"-"`
+
+	// Executor runs the external commands, the default runs them as child processes.
+	Executor Executor `json:"-"`
+	// Templates is an optional prefetcher, which may already have downloaded the template.
+	Templates *Prefetcher `json:"-"`
+	// OnEvent is an optional callback, called with the progress and the result of the creation.
+	OnEvent func(*Event) `json:"-"`
+}
+
+// Host returns the hosting service of the repository.
+// An invalid RepoHost (reported by Validate) results in github.com.
+func (opts *Options) Host() *Host {
+	host, err := ParseHost(opts.RepoHost)
+	if err != nil {
+		host, _ = ParseHost("")
+	}
+
+	return host
+}
+
+// GuessUseGitHub enables repository hosting if the repository owner, name and protocol are known.
+func (opts *Options) GuessUseGitHub() {
+	opts.UseGitHub = opts.UseGitHub ||
+		((len(opts.RepoOwner) != 0) && (len(opts.RepoName) != 0) && (len(opts.RepoProtocol) != 0))
+}
+
+// GuessKind guesses the extension type from the directory name.
+func (opts *Options) GuessKind() {
+	if len(opts.Kind) != 0 || len(opts.Dir) == 0 {
+		return
+	}
+
+	dir := filepath.Base(opts.Dir)
+
+	if strings.HasPrefix(dir, prefixJavaScript) {
+		if strings.HasPrefix(dir, prefixOutput) {
+			opts.Kind = Output
+		} else {
+			opts.Kind = JavaScript
+		}
+	} else {
+		opts.Kind = JavaScript
+	}
+}
+
+// GuessName guesses the extension name from the directory name.
+func (opts *Options) GuessName() {
+	if len(opts.Name) != 0 || len(opts.Dir) == 0 {
+		return
+	}
+
+	dir := filepath.Base(opts.Dir)
+
+	if strings.HasPrefix(dir, "xk6-") {
+		if strings.HasPrefix(dir, "xk6-output-") {
+			opts.Name = strings.TrimPrefix(dir, "xk6-output-")
+		} else {
+			opts.Name = strings.TrimPrefix(dir, "xk6-")
+		}
+	}
+}
+
+// GuessRepoName derives the repository name from the extension type and name.
+func (opts *Options) GuessRepoName() {
+	if len(opts.RepoName) != 0 {
+		return
+	}
+
+	opts.RepoName = opts.Kind.RepoNamePrefix() + opts.Name
+}
+
+// GuessGoModule derives the go module path from the repository host, owner and name.
+func (opts *Options) GuessGoModule() {
+	if len(opts.GoModule) != 0 {
+		return
+	}
+
+	opts.GuessUseGitHub()
+	opts.GuessName()
+
+	if len(opts.RepoOwner) != 0 && len(opts.RepoName) != 0 {
+		opts.GoModule = opts.Host().GoModule(opts.RepoOwner, opts.RepoName)
+	} else if len(opts.Name) != 0 {
+		opts.GoModule = opts.Kind.RepoNamePrefix() + opts.Name
+	}
+}
+
+// GuessGoPackage derives the go package name from the extension name.
+func (opts *Options) GuessGoPackage() {
+	if len(opts.GoPackage) != 0 || len(opts.Name) == 0 {
+		return
+	}
+
+	opts.GoPackage = strcase.ToSnake(opts.Name)
+}
+
+// GuessGitOrigin derives the git origin URL from the repository host, owner, name and protocol.
+func (opts *Options) GuessGitOrigin() {
+	opts.GuessUseGitHub()
+
+	if len(opts.GitOrigin) != 0 || !opts.UseGitHub {
+		return
+	}
+
+	if len(opts.RepoOwner) == 0 || len(opts.RepoName) == 0 || len(opts.RepoProtocol) == 0 {
+		return
+	}
+
+	opts.GitOrigin = opts.Host().GitOrigin(opts.RepoProtocol, opts.RepoOwner, opts.RepoName)
+}
+
+// GuessPrimaryClass derives the primary JavaScript class name from the extension name.
+func (opts *Options) GuessPrimaryClass() {
+	if len(opts.PrimaryClass) != 0 {
+		return
+	}
+
+	opts.PrimaryClass = strcase.ToCamel(opts.Name)
+}
+
+// GuessDir derives the directory name from the extension type and name.
+func (opts *Options) GuessDir() {
+	if len(opts.Dir) != 0 {
+		return
+	}
+
+	opts.Dir = opts.Kind.RepoNamePrefix() + opts.Name
+}
+
+// Update recalculates the values that always follow the other values (the environment variable prefix).
+func (opts *Options) Update() {
+	from := opts.RepoName
+	if len(from) == 0 {
+		from = opts.Kind.RepoNamePrefix() + "_" + opts.Name
+	}
+
+	opts.EnvPrefix = strcase.ToScreamingDelimited(from, '_', "xk6", true)
+}
+
+// Guess derives the missing values from the given ones.
+func (opts *Options) Guess() {
+	opts.GuessKind()
+	opts.GuessName()
+	opts.GuessRepoName()
+	opts.GuessUseGitHub()
+	opts.GuessGoModule()
+	opts.GuessGoPackage()
+	opts.GuessGitOrigin()
+	opts.GuessPrimaryClass()
+	opts.GuessDir()
+}
+
+func (opts *Options) toMap() (map[string]interface{}, error) {
+	buff, err := json.Marshal(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var data map[string]interface{}
+
+	err = json.Unmarshal(buff, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// Kind is the type of the extension.
+type Kind string
+
+// RepoNamePrefix returns the repository name prefix of the extension type.
+func (k Kind) RepoNamePrefix() string {
+	if k == JavaScript {
+		return prefixJavaScript
+	}
+
+	return prefixOutput
+}
+
+const (
+	// JavaScript extensions extend the JavaScript APIs available to the test scripts.
+	JavaScript Kind = "JavaScript"
+	// Output extensions send metrics to a custom file format or service.
+	Output Kind = "Output"
+
+	prefixJavaScript = "xk6-"
+	prefixOutput     = prefixJavaScript + "output-"
+)
@@ -1,4 +1,4 @@
-package main
+package scaffold
 
 import (
 	"testing"
@@ -9,7 +9,7 @@ import (
 func Test_options_toMap_empty(t *testing.T) {
 	t.Parallel()
 
-	opts := new(options)
+	opts := new(Options)
 
 	data, err := opts.toMap()
 
@@ -20,8 +20,8 @@ func Test_options_toMap_empty(t *testing.T) {
 func Test_options_toMap_withValues(t *testing.T) {
 	t.Parallel()
 
-	opts := &options{
-		Kind:         javascript,
+	opts := &Options{
+		Kind:         JavaScript,
 		Name:         "hitchhiker",
 		PrimaryClass: "Guide",
 		RepoName:     "xk6-hitchhiker",
@@ -30,7 +30,7 @@ func Test_options_toMap_withValues(t *testing.T) {
 	data, err := opts.toMap()
 
 	expected := map[string]interface{}{
-		"kind":         string(javascript),
+		"kind":         string(JavaScript),
 		"name":         "hitchhiker",
 		"PrimaryClass": "Guide",
 		"repoName":     "xk6-hitchhiker",
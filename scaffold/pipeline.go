@@ -0,0 +1,165 @@
+package scaffold
+
+import (
+	"context"
+	"time"
+)
+
+// Pipeline runs a series of steps and reports their progress as events.
+// The output of the commands executed by a step is attached to the step's finish event.
+type Pipeline struct {
+	policy
+
+	ctx      context.Context //nolint:containedctx
+	executor Executor
+	onEvent  func(*Event)
+
+	// step is the name of the running step
+	step  string
+	debug []byte
+}
+
+// NewPipeline returns a new Pipeline.
+// A nil executor runs the commands as child processes, a nil onEvent ignores the events.
+func NewPipeline(ctx context.Context, exec Executor, onEvent func(*Event)) *Pipeline {
+	if exec == nil {
+		exec = NewExecutor()
+	}
+
+	if onEvent == nil {
+		onEvent = func(*Event) {}
+	}
+
+	return &Pipeline{ctx: ctx, executor: exec, onEvent: onEvent, policy: policy{backoff: defaultBackoff}}
+}
+
+func (p *Pipeline) emit(e *Event) {
+	e.Time = time.Now()
+
+	p.onEvent(e)
+}
+
+// Begin reports the start of a series of steps.
+func (p *Pipeline) Begin(title string) {
+	p.emit(&Event{Type: EventBegin, Title: title})
+}
+
+// Step runs fn as the named step.
+// The step is not started if the context is already done.
+// The error of a failed step is returned as *StepError,
+// with the context's error as cause if the step failed because the context is done.
+func (p *Pipeline) Step(name string, fn func() error) error {
+	if err := p.ctx.Err(); err != nil {
+		return &StepError{Step: name, Err: err}
+	}
+
+	p.emit(&Event{Type: EventStart, Step: name})
+
+	started := time.Now()
+
+	p.step = name
+	err := fn()
+	p.step = ""
+
+	if err != nil && p.ctx.Err() != nil {
+		err = p.ctx.Err()
+	}
+
+	p.emit(&Event{Type: EventFinish, Step: name, Duration: time.Since(started), Output: p.debug, Err: err})
+
+	out := p.debug
+	p.debug = nil
+
+	if err != nil {
+		return &StepError{Step: name, Output: out, Err: err}
+	}
+
+	return nil
+}
+
+// Run runs the command in dir and attaches its output to the current step.
+func (p *Pipeline) Run(dir string, name string, args ...string) error {
+	ctx, cancel := p.withTimeout(p.ctx, p.step)
+	defer cancel()
+
+	var err error
+
+	p.debug, err = p.executor.Run(ctx, dir, name, args...)
+
+	return p.timedOut(ctx, p.step, err)
+}
+
+// Output runs the command in dir and returns its standard output.
+func (p *Pipeline) Output(dir string, name string, args ...string) ([]byte, error) {
+	ctx, cancel := p.withTimeout(p.ctx, p.step)
+	defer cancel()
+
+	out, err := p.executor.Output(ctx, dir, name, args...)
+
+	return out, p.timedOut(ctx, p.step, err)
+}
+
+// task is a command running in the background.
+type task struct {
+	done   chan struct{}
+	cancel context.CancelFunc
+	out    []byte
+	err    error
+
+	// retries are the retry events, reported when the task is waited for
+	retries []*Event
+}
+
+// startTask runs fn in the background with a context canceled by the task's cancel function.
+// The task's fields may be accessed by fn, and by the others only after the task is done.
+func startTask(ctx context.Context, fn func(ctx context.Context, t *task) ([]byte, error)) *task {
+	ctx, cancel := context.WithCancel(ctx)
+
+	t := &task{done: make(chan struct{}), cancel: cancel}
+
+	go func() {
+		defer close(t.done)
+
+		t.out, t.err = fn(ctx, t)
+	}()
+
+	return t
+}
+
+// stop cancels the task and waits for it to finish.
+func (t *task) stop() {
+	t.cancel()
+
+	<-t.done
+}
+
+// background runs the command of the step in the background, retried as a network-bound step.
+// The task must be stopped, even if it is not waited for.
+// The retries are reported by wait, so the events of the steps running in the meantime are not interleaved.
+func (p *Pipeline) background(step string, dir string, name string, args ...string) *task {
+	return startTask(p.ctx, func(ctx context.Context, t *task) ([]byte, error) {
+		return p.retryContext(ctx, step, func(e *Event) { t.retries = append(t.retries, e) },
+			func(ctx context.Context) ([]byte, error) {
+				return p.executor.Run(ctx, dir, name, args...)
+			},
+		)
+	})
+}
+
+// wait waits for the background task to finish and attaches its output to the current step.
+// It stops waiting if the context is done.
+func (p *Pipeline) wait(t *task) error {
+	select {
+	case <-t.done:
+	case <-p.ctx.Done():
+		return p.ctx.Err()
+	}
+
+	for _, e := range t.retries {
+		p.emit(e)
+	}
+
+	p.debug = t.out
+
+	return t.err
+}
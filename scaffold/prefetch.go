@@ -0,0 +1,157 @@
+//nolint:forbidigo
+package scaffold
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// templateFetch is a template download running in the background.
+type templateFetch struct {
+	*task
+	kind Kind
+	dir  string
+}
+
+func fetchTemplate(ctx context.Context, exec Executor, k Kind) *templateFetch {
+	fetch := &templateFetch{kind: k}
+
+	fetch.task = startTask(ctx, func(ctx context.Context, _ *task) ([]byte, error) {
+		dir, err := os.MkdirTemp("", "template-"+strings.ToLower(string(k))+"-")
+		if err != nil {
+			return nil, err
+		}
+
+		out, err := exec.Run(ctx, "", "git", "clone", "--depth", "1", k.template().repo, dir)
+		if err != nil {
+			_ = os.RemoveAll(dir)
+
+			return out, err
+		}
+
+		if err = os.RemoveAll(filepath.Join(dir, ".git")); err != nil {
+			return out, err
+		}
+
+		fetch.dir = dir
+
+		return out, nil
+	})
+
+	return fetch
+}
+
+// discard cancels the download, waits for it to finish and removes the downloaded template.
+func (fetch *templateFetch) discard() {
+	fetch.stop()
+
+	if len(fetch.dir) != 0 {
+		_ = os.RemoveAll(fetch.dir)
+	}
+}
+
+// Prefetcher downloads the template in the background, for example while the questions are being answered.
+// The download starts as soon as the extension type is known, and restarts if it changes.
+// A nil Prefetcher is valid and never has a template ready.
+type Prefetcher struct {
+	executor Executor
+	mu       sync.Mutex
+	current  *templateFetch
+}
+
+// NewPrefetcher returns a new Prefetcher using the given executor.
+func NewPrefetcher(exec Executor) *Prefetcher {
+	return &Prefetcher{executor: exec}
+}
+
+// Start starts downloading the template of the given extension type.
+func (p *Prefetcher) Start(k Kind) {
+	if p == nil || k.template() == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.current != nil {
+		if p.current.kind == k {
+			return
+		}
+
+		go p.current.discard()
+	}
+
+	p.current = fetchTemplate(context.Background(), p.executor, k)
+}
+
+// take returns the download of the given type of template, if there is one in progress or completed.
+// The caller becomes responsible for the downloaded template.
+func (p *Prefetcher) take(k Kind) *templateFetch {
+	if p == nil {
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	fetch := p.current
+	if fetch == nil || fetch.kind != k {
+		return nil
+	}
+
+	p.current = nil
+
+	return fetch
+}
+
+// Close discards the download that has not been taken.
+func (p *Prefetcher) Close() {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	fetch := p.current
+	p.current = nil
+	p.mu.Unlock()
+
+	if fetch != nil {
+		fetch.discard()
+	}
+}
+
+// DownloadTemplate downloads the template of the given extension type into a temporary directory.
+// The caller is responsible for removing the returned directory.
+func DownloadTemplate(ctx context.Context, exec Executor, k Kind) (string, error) {
+	if k.template() == nil {
+		return "", fmt.Errorf("%w: %s", ErrUnknownKind, k)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	if exec == nil {
+		exec = NewExecutor()
+	}
+
+	fetch := fetchTemplate(ctx, exec, k)
+
+	select {
+	case <-fetch.done:
+	case <-ctx.Done():
+		fetch.discard()
+
+		return "", ctx.Err()
+	}
+
+	if fetch.err != nil {
+		return "", fetch.err
+	}
+
+	return fetch.dir, nil
+}
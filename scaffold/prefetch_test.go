@@ -0,0 +1,140 @@
+package scaffold
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/szkiba/create-k6-extension/scaffold/scaffoldtest"
+)
+
+func clones(lines []string) []string {
+	var result []string
+
+	for _, line := range lines {
+		if strings.HasPrefix(line, "git clone") {
+			result = append(result, line)
+		}
+	}
+
+	return result
+}
+
+func Test_prefetcher(t *testing.T) {
+	t.Parallel()
+
+	exec := scaffoldtest.NewExecutor()
+	p := NewPrefetcher(exec)
+
+	p.Start(JavaScript)
+	p.Start(JavaScript)
+
+	assert.Nil(t, p.take(Output))
+
+	fetch := p.take(JavaScript)
+
+	require.NotNil(t, fetch)
+	<-fetch.done
+	require.NoError(t, fetch.err)
+	assert.DirExists(t, fetch.dir)
+	assert.Nil(t, p.take(JavaScript))
+
+	fetch.discard()
+
+	assert.NoDirExists(t, fetch.dir)
+	assert.Len(t, clones(exec.Lines()), 1)
+}
+
+func Test_prefetcher_restart(t *testing.T) {
+	t.Parallel()
+
+	exec := scaffoldtest.NewExecutor()
+	p := NewPrefetcher(exec)
+
+	started := make(chan struct{})
+
+	exec.Hooks["xk6-template-javascript"] = func(_ string, _ []string) error {
+		close(started)
+
+		return nil
+	}
+
+	p.Start(JavaScript)
+
+	first := p.current
+
+	// the first download is recorded only if it has been started before the restart
+	<-started
+
+	p.Start(Output)
+
+	assert.Nil(t, p.take(JavaScript))
+
+	<-first.done
+
+	fetch := p.take(Output)
+
+	require.NotNil(t, fetch)
+	<-fetch.done
+
+	lines := clones(exec.Lines())
+
+	require.Len(t, lines, 2)
+	assert.Contains(t, strings.Join(lines, "\n"), "xk6-template-javascript.git")
+	assert.Contains(t, strings.Join(lines, "\n"), "xk6-template-output.git")
+
+	fetch.discard()
+}
+
+func Test_prefetcher_close(t *testing.T) {
+	t.Parallel()
+
+	p := NewPrefetcher(scaffoldtest.NewExecutor())
+
+	p.Start(Output)
+
+	fetch := p.current
+
+	p.Close()
+
+	_, err := os.Stat(fetch.dir)
+
+	assert.True(t, os.IsNotExist(err))
+	assert.Nil(t, p.take(Output))
+}
+
+func Test_create_prefetched(t *testing.T) {
+	t.Parallel()
+
+	opts, exec := fakeCreateOptions(t)
+
+	opts.NoInstall = true
+	opts.Templates = NewPrefetcher(exec)
+	opts.Templates.Start(JavaScript)
+
+	_, err := Create(context.Background(), opts)
+
+	require.NoError(t, err)
+
+	assert.Len(t, clones(exec.Lines()), 1)
+	assert.FileExists(t, opts.Dir+"/foo.go")
+}
+
+func Test_DownloadTemplate(t *testing.T) {
+	t.Parallel()
+
+	exec := scaffoldtest.NewExecutor()
+
+	dir, err := DownloadTemplate(context.Background(), exec, Output)
+
+	require.NoError(t, err)
+	assert.DirExists(t, dir)
+	assert.NoError(t, os.RemoveAll(dir))
+
+	_, err = DownloadTemplate(context.Background(), exec, "Unknown")
+
+	assert.ErrorIs(t, err, ErrUnknownKind)
+}
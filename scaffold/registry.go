@@ -0,0 +1,130 @@
+//nolint:forbidigo
+package scaffold
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// RegistryEntry is the metadata of the extension required by the k6 extension registry.
+type RegistryEntry struct {
+	// Module is the go module path of the extension.
+	Module string `json:"module"`
+	// Description is a brief summary of the extension.
+	Description string `json:"description"`
+	// Imports contains the JavaScript module names of a JavaScript extension.
+	Imports []string `json:"imports,omitempty"`
+	// Outputs contains the output names of an Output extension.
+	Outputs []string `json:"outputs,omitempty"`
+	// Tier is the support level of the extension (official, partner or community).
+	Tier string `json:"tier"`
+	// Categories contains the registry categories of the extension.
+	Categories []string `json:"categories"`
+}
+
+// RegistryEntry returns the registry metadata derived from the options.
+// Extensions owned by the grafana organization on github.com are official, the others are community extensions.
+// Without categories, the misc category is used.
+func (opts *Options) RegistryEntry() *RegistryEntry {
+	entry := &RegistryEntry{
+		Module:      opts.GoModule,
+		Description: opts.Summary,
+		Tier:        TierCommunity,
+		Categories:  opts.Categories,
+	}
+
+	if opts.Kind == JavaScript {
+		entry.Imports = []string{jsModulePrefix + opts.Name}
+	} else {
+		entry.Outputs = []string{opts.Name}
+	}
+
+	if opts.RepoOwner == "grafana" && opts.Host().Domain == "github.com" {
+		entry.Tier = TierOfficial
+	}
+
+	if len(entry.Categories) == 0 {
+		entry.Categories = []string{"misc"}
+	}
+
+	return entry
+}
+
+// ReadRegistryEntry reads the registry metadata file (RegistryEntryFile) from the extension directory.
+func ReadRegistryEntry(dir string) (*RegistryEntry, error) {
+	data, err := os.ReadFile(filepath.Join(dir, RegistryEntryFile)) //nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+
+	entry := new(RegistryEntry)
+
+	if err := json.Unmarshal(data, entry); err != nil {
+		return nil, fmt.Errorf("%s: %w", RegistryEntryFile, err)
+	}
+
+	return entry, nil
+}
+
+// WriteRegistryEntry writes the registry metadata file (RegistryEntryFile) into the extension directory.
+func WriteRegistryEntry(dir string, entry *RegistryEntry) error {
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dir, RegistryEntryFile), append(data, '\n'), 0o600)
+}
+
+// CheckCategories checks that all the categories are known registry categories.
+func CheckCategories(categories []string) error {
+	for _, category := range categories {
+		if !isCategory(category) {
+			return fmt.Errorf("%w: %s (known categories: %s)",
+				errUnknownCategory, category, strings.Join(Categories(), ", "))
+		}
+	}
+
+	return nil
+}
+
+func isCategory(category string) bool {
+	for _, known := range Categories() {
+		if category == known {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Categories returns the categories of the k6 extension registry.
+func Categories() []string {
+	return []string{
+		"authentication",
+		"browser",
+		"data",
+		"kubernetes",
+		"messaging",
+		"misc",
+		"observability",
+		"protocol",
+		"reporting",
+	}
+}
+
+const (
+	// RegistryEntryFile is the name of the registry metadata file in the extension directory.
+	RegistryEntryFile = "registry-entry.json"
+
+	// TierOfficial is the tier of the extensions maintained by Grafana Labs.
+	TierOfficial = "official"
+	// TierCommunity is the tier of the extensions maintained by the community.
+	TierCommunity = "community"
+)
+
+var errUnknownCategory = errors.New("unknown category")
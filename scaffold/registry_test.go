@@ -0,0 +1,89 @@
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Options_RegistryEntry(t *testing.T) {
+	t.Parallel()
+
+	opts := &Options{
+		Kind:       JavaScript,
+		Name:       "foo",
+		Summary:    "Foo extension",
+		RepoOwner:  "acme",
+		GoModule:   "github.com/acme/xk6-foo",
+		Categories: []string{"data", "protocol"},
+	}
+
+	assert.Equal(t, &RegistryEntry{
+		Module:      "github.com/acme/xk6-foo",
+		Description: "Foo extension",
+		Imports:     []string{"k6/x/foo"},
+		Tier:        TierCommunity,
+		Categories:  []string{"data", "protocol"},
+	}, opts.RegistryEntry())
+
+	opts = &Options{Kind: Output, Name: "bar", RepoOwner: "grafana", GoModule: "github.com/grafana/xk6-output-bar"}
+
+	assert.Equal(t, &RegistryEntry{
+		Module:     "github.com/grafana/xk6-output-bar",
+		Outputs:    []string{"bar"},
+		Tier:       TierOfficial,
+		Categories: []string{"misc"},
+	}, opts.RegistryEntry())
+
+	opts.RepoHost = "gitlab.com"
+	opts.GoModule = "gitlab.com/grafana/xk6-output-bar"
+
+	assert.Equal(t, TierCommunity, opts.RegistryEntry().Tier)
+}
+
+func Test_WriteRegistryEntry(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	entry := &RegistryEntry{
+		Module:      "github.com/acme/xk6-foo",
+		Description: "Foo extension",
+		Imports:     []string{"k6/x/foo"},
+		Tier:        TierCommunity,
+		Categories:  []string{"misc"},
+	}
+
+	require.NoError(t, WriteRegistryEntry(dir, entry))
+
+	content, err := os.ReadFile(filepath.Join(dir, RegistryEntryFile))
+
+	require.NoError(t, err)
+	assert.Equal(t, `{
+  "module": "github.com/acme/xk6-foo",
+  "description": "Foo extension",
+  "imports": [
+    "k6/x/foo"
+  ],
+  "tier": "community",
+  "categories": [
+    "misc"
+  ]
+}
+`, string(content))
+
+	actual, err := ReadRegistryEntry(dir)
+
+	require.NoError(t, err)
+	assert.Equal(t, entry, actual)
+}
+
+func Test_CheckCategories(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, CheckCategories(nil))
+	assert.NoError(t, CheckCategories([]string{"data", "misc"}))
+	assert.ErrorIs(t, CheckCategories([]string{"data", "fun"}), errUnknownCategory)
+}
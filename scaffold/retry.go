@@ -0,0 +1,142 @@
+package scaffold
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// policy contains the timeouts of the steps and the retry settings of the network-bound steps.
+type policy struct {
+	timeout      time.Duration
+	stepTimeouts map[string]time.Duration
+	retries      int
+	backoff      time.Duration
+}
+
+const defaultBackoff = time.Second
+
+func newPolicy(opts *Options) policy {
+	p := policy{
+		timeout:      opts.Timeout,
+		stepTimeouts: make(map[string]time.Duration, len(opts.StepTimeouts)),
+		retries:      opts.Retries,
+		backoff:      opts.RetryBackoff,
+	}
+
+	for name, timeout := range opts.StepTimeouts {
+		p.stepTimeouts[StepKey(name)] = timeout
+	}
+
+	if p.backoff <= 0 {
+		p.backoff = defaultBackoff
+	}
+
+	return p
+}
+
+// stepTimeout returns the timeout of the commands run by the step, zero means no limit.
+func (p *policy) stepTimeout(step string) time.Duration {
+	if timeout, found := p.stepTimeouts[StepKey(step)]; found {
+		return timeout
+	}
+
+	return p.timeout
+}
+
+// StepKey returns the step name in the lower case, dash separated form,
+// so "Install xk6" and "install-xk6" are the same step.
+func StepKey(name string) string {
+	return strings.ToLower(strings.NewReplacer(" ", "-", "_", "-").Replace(strings.TrimSpace(name)))
+}
+
+// CheckStep checks that the step (given by its title or by its StepKey) is run by Create.
+func CheckStep(name string) error {
+	for _, step := range steps() {
+		if StepKey(step) == StepKey(name) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %s", errUnknownStep, name)
+}
+
+// withTimeout returns a context limited by the timeout of the step.
+func (p *policy) withTimeout(ctx context.Context, step string) (context.Context, context.CancelFunc) {
+	timeout := p.stepTimeout(step)
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
+
+// timedOut replaces the error of a command killed because of the step timeout with ErrTimeout.
+func (p *policy) timedOut(ctx context.Context, step string, err error) error {
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%w after %s", ErrTimeout, p.stepTimeout(step))
+	}
+
+	return err
+}
+
+// retry runs fn until it succeeds or the retries are exhausted, waiting with exponential backoff between the attempts.
+// Each attempt is limited by the timeout of the step.
+// The returned output contains the output of all attempts and a note about each retry,
+// and each retry is also reported as an EventRetry.
+func (p *Pipeline) retry(step string, fn func(ctx context.Context) ([]byte, error)) ([]byte, error) {
+	return p.retryContext(p.ctx, step, p.emit, fn)
+}
+
+// retryContext is the same as retry, but the attempts are run with the given context instead of the pipeline's,
+// and the retries are reported to onRetry.
+func (p *Pipeline) retryContext(
+	parent context.Context,
+	step string,
+	onRetry func(*Event),
+	fn func(ctx context.Context) ([]byte, error),
+) ([]byte, error) {
+	var buff bytes.Buffer
+
+	delay := p.backoff
+
+	for attempt := 1; ; attempt++ {
+		ctx, cancel := p.withTimeout(parent, step)
+		out, err := fn(ctx)
+		err = p.timedOut(ctx, step, err)
+
+		cancel()
+		buff.Write(out)
+
+		if err == nil || attempt > p.retries || parent.Err() != nil {
+			return buff.Bytes(), err
+		}
+
+		fmt.Fprintf(&buff, "attempt %d of %d failed: %s, retrying in %s\n", attempt, p.retries+1, err, delay)
+
+		onRetry(&Event{
+			Type:     EventRetry,
+			Step:     step,
+			Duration: delay,
+			Attempt:  attempt,
+			Attempts: p.retries + 1,
+			Err:      err,
+		})
+
+		select {
+		case <-time.After(delay):
+		case <-parent.Done():
+			return buff.Bytes(), parent.Err()
+		}
+
+		delay *= 2
+	}
+}
+
+var errUnknownStep = errors.New("unknown step")
+
+// ErrTimeout is the cause of a step failed because of the step timeout.
+var ErrTimeout = errors.New("timed out")
@@ -0,0 +1,118 @@
+package scaffold
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_policy_stepTimeout(t *testing.T) {
+	t.Parallel()
+
+	p := newPolicy(&Options{
+		Timeout:      time.Minute,
+		StepTimeouts: map[string]time.Duration{"install-xk6": time.Hour, "Download template": time.Second},
+	})
+
+	assert.Equal(t, time.Hour, p.stepTimeout("Install xk6"))
+	assert.Equal(t, time.Second, p.stepTimeout("Download template"))
+	assert.Equal(t, time.Minute, p.stepTimeout("Generate sources"))
+	assert.Equal(t, defaultBackoff, p.backoff)
+	assert.Equal(t, "install-xk6", StepKey("Install xk6"))
+}
+
+func Test_Pipeline_retry(t *testing.T) {
+	t.Parallel()
+
+	var recorded events
+
+	p := NewPipeline(context.Background(), nil, recorded.record)
+	p.policy = newPolicy(&Options{Retries: 2, RetryBackoff: time.Millisecond})
+
+	errFake := errors.New("fake failure")
+
+	attempts := 0
+
+	out, err := p.retry("Install xk6", func(context.Context) ([]byte, error) {
+		attempts++
+
+		if attempts < 3 {
+			return []byte("proxy error\n"), errFake
+		}
+
+		return []byte("ok\n"), nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+	assert.Equal(t, `proxy error
+attempt 1 of 3 failed: fake failure, retrying in 1ms
+proxy error
+attempt 2 of 3 failed: fake failure, retrying in 2ms
+ok
+`, string(out))
+
+	require.Len(t, recorded, 2)
+
+	for idx, e := range recorded {
+		assert.Equal(t, EventRetry, e.Type)
+		assert.Equal(t, "Install xk6", e.Step)
+		assert.Equal(t, idx+1, e.Attempt)
+		assert.Equal(t, 3, e.Attempts)
+		assert.Equal(t, time.Millisecond<<idx, e.Duration)
+		assert.ErrorIs(t, e.Err, errFake)
+	}
+
+	attempts = 0
+
+	_, err = p.retry("Install xk6", func(context.Context) ([]byte, error) {
+		attempts++
+
+		return nil, errFake
+	})
+
+	assert.ErrorIs(t, err, errFake)
+	assert.Equal(t, 3, attempts)
+}
+
+func Test_Pipeline_retry_timeout(t *testing.T) {
+	t.Parallel()
+
+	p := NewPipeline(context.Background(), nil, nil)
+	p.policy = newPolicy(&Options{StepTimeouts: map[string]time.Duration{"download-template": time.Millisecond}})
+
+	_, err := p.retry("Download template", func(ctx context.Context) ([]byte, error) {
+		<-ctx.Done()
+
+		return nil, errors.New("signal: killed")
+	})
+
+	assert.ErrorIs(t, err, ErrTimeout)
+	assert.Equal(t, "timed out after 1ms", err.Error())
+}
+
+func Test_Pipeline_retry_canceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	p := NewPipeline(ctx, nil, nil)
+	p.policy = newPolicy(&Options{Retries: 5, RetryBackoff: time.Hour})
+
+	attempts := 0
+
+	_, err := p.retry("Install xk6", func(context.Context) ([]byte, error) {
+		attempts++
+
+		cancel()
+
+		return nil, errors.New("signal: killed")
+	})
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, attempts)
+}
@@ -0,0 +1,109 @@
+// Package scaffoldtest provides utilities for testing code that uses the scaffold package.
+package scaffoldtest
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Call is a command executed by Executor.
+type Call struct {
+	Dir  string
+	Line string
+}
+
+// Executor is a scaffold.Executor that records the commands instead of running them.
+// The responses are selected by matching the command line against regular expressions.
+// If more than one output pattern matches, the longest pattern wins.
+type Executor struct {
+	mu    sync.Mutex
+	calls []Call
+
+	// Outputs contains the output of the matching commands.
+	Outputs map[string]string
+	// Failures contains the error of the matching commands.
+	Failures map[string]error
+	// Hooks contains functions called before the matching commands return.
+	Hooks map[string]func(dir string, args []string) error
+}
+
+// NewExecutor returns a new Executor without any response.
+func NewExecutor() *Executor {
+	return &Executor{
+		Outputs:  make(map[string]string),
+		Failures: make(map[string]error),
+		Hooks:    make(map[string]func(dir string, args []string) error),
+	}
+}
+
+// Run records the command and returns the matching response.
+// The command is not recorded if the context is already done.
+func (e *Executor) Run(ctx context.Context, dir string, name string, args ...string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	line := strings.Join(append([]string{name}, args...), " ")
+
+	e.mu.Lock()
+	e.calls = append(e.calls, Call{Dir: dir, Line: line})
+	e.mu.Unlock()
+
+	for pattern, hook := range e.Hooks {
+		if matches(pattern, line) {
+			if err := hook(dir, args); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var out []byte
+
+	longest := -1
+
+	for pattern, str := range e.Outputs {
+		if matches(pattern, line) && len(pattern) > longest {
+			out, longest = []byte(str), len(pattern)
+		}
+	}
+
+	for pattern, err := range e.Failures {
+		if matches(pattern, line) {
+			return out, err
+		}
+	}
+
+	return out, nil
+}
+
+func matches(pattern, line string) bool {
+	return regexp.MustCompile(pattern).MatchString(line)
+}
+
+// Output is the same as Run.
+func (e *Executor) Output(ctx context.Context, dir string, name string, args ...string) ([]byte, error) {
+	return e.Run(ctx, dir, name, args...)
+}
+
+// Calls returns the recorded commands.
+func (e *Executor) Calls() []Call {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return append([]Call(nil), e.calls...)
+}
+
+// Lines returns the recorded command lines.
+func (e *Executor) Lines() []string {
+	calls := e.Calls()
+
+	lines := make([]string, 0, len(calls))
+
+	for _, call := range calls {
+		lines = append(lines, call.Line)
+	}
+
+	return lines
+}
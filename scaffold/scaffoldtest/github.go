@@ -0,0 +1,179 @@
+package scaffoldtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+)
+
+// Repository is a repository stored by GitHub.
+type Repository struct {
+	FullName    string   `json:"full_name"`
+	HTMLURL     string   `json:"html_url"`
+	Description string   `json:"description"`
+	Topics      []string `json:"topics"`
+}
+
+// GitHub is a local stand-in for the GitHub REST API, which stores the repositories in memory.
+// Only the endpoints used by the scaffold package are implemented.
+type GitHub struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	repos    map[string]*Repository
+	requests []string
+
+	// Login is the login name of the authenticated user.
+	Login string
+	// Token is the accepted access token.
+	Token string
+}
+
+// NewGitHub starts a new GitHub stand-in server, which accepts the token of the given user.
+// The server should be closed after use.
+func NewGitHub(login, token string) *GitHub {
+	gh := &GitHub{repos: make(map[string]*Repository), Login: login, Token: token}
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/user", gh.user)
+	mux.HandleFunc("/user/repos", gh.createRepo)
+	mux.HandleFunc("/orgs/", gh.createRepo)
+	mux.HandleFunc("/repos/", gh.repo)
+
+	gh.Server = httptest.NewServer(gh.authenticate(mux))
+
+	return gh
+}
+
+// AddRepository stores an existing repository.
+func (gh *GitHub) AddRepository(fullName, description string, topics ...string) {
+	gh.mu.Lock()
+	defer gh.mu.Unlock()
+
+	gh.repos[fullName] = &Repository{
+		FullName:    fullName,
+		HTMLURL:     "https://github.com/" + fullName,
+		Description: description,
+		Topics:      topics,
+	}
+}
+
+// Repository returns the stored repository or nil.
+func (gh *GitHub) Repository(fullName string) *Repository {
+	gh.mu.Lock()
+	defer gh.mu.Unlock()
+
+	return gh.repos[fullName]
+}
+
+// Requests returns the received requests as method and path.
+func (gh *GitHub) Requests() []string {
+	gh.mu.Lock()
+	defer gh.mu.Unlock()
+
+	return append([]string(nil), gh.requests...)
+}
+
+func (gh *GitHub) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gh.mu.Lock()
+		gh.requests = append(gh.requests, r.Method+" "+r.URL.Path)
+		gh.mu.Unlock()
+
+		if r.Header.Get("Authorization") != "Bearer "+gh.Token {
+			reply(w, http.StatusUnauthorized, map[string]string{"message": "Bad credentials"})
+
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (gh *GitHub) user(w http.ResponseWriter, _ *http.Request) {
+	reply(w, http.StatusOK, map[string]string{"login": gh.Login})
+}
+
+func (gh *GitHub) createRepo(w http.ResponseWriter, r *http.Request) {
+	owner := gh.Login
+
+	if strings.HasPrefix(r.URL.Path, "/orgs/") {
+		owner = strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/orgs/"), "/repos")
+	}
+
+	var body struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	}
+
+	if r.Method != http.MethodPost || json.NewDecoder(r.Body).Decode(&body) != nil {
+		reply(w, http.StatusBadRequest, map[string]string{"message": "Bad request"})
+
+		return
+	}
+
+	name := owner + "/" + body.Name
+
+	if gh.Repository(name) != nil {
+		reply(w, http.StatusUnprocessableEntity, map[string]string{"message": "name already exists on this account"})
+
+		return
+	}
+
+	gh.AddRepository(name, body.Description)
+
+	reply(w, http.StatusCreated, gh.Repository(name))
+}
+
+func (gh *GitHub) repo(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/repos/")
+	name, topics := strings.CutSuffix(path, "/topics")
+
+	repo := gh.Repository(name)
+	if repo == nil {
+		reply(w, http.StatusNotFound, map[string]string{"message": "Not Found"})
+
+		return
+	}
+
+	gh.mu.Lock()
+	defer gh.mu.Unlock()
+
+	var body struct {
+		Description *string  `json:"description"`
+		Names       []string `json:"names"`
+	}
+
+	if r.Method != http.MethodGet && json.NewDecoder(r.Body).Decode(&body) != nil {
+		reply(w, http.StatusBadRequest, map[string]string{"message": "Bad request"})
+
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && !topics:
+		reply(w, http.StatusOK, repo)
+	case r.Method == http.MethodPatch && !topics:
+		if body.Description != nil {
+			repo.Description = *body.Description
+		}
+
+		reply(w, http.StatusOK, repo)
+	case r.Method == http.MethodPut && topics:
+		repo.Topics = body.Names
+
+		reply(w, http.StatusOK, map[string][]string{"names": repo.Topics})
+	default:
+		reply(w, http.StatusMethodNotAllowed, map[string]string{"message": "Method not allowed"})
+	}
+}
+
+func reply(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(body)
+}
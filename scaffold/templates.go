@@ -0,0 +1,57 @@
+package scaffold
+
+import (
+	"fmt"
+	"strings"
+)
+
+// templateSpec describes a template repository used to create extensions.
+type templateSpec struct {
+	kind Kind
+	repo string
+
+	// minVersions contains the minimum versions of the tools required by the template,
+	// indexed by tool (go, git).
+	minVersions map[string]string
+}
+
+func templateSpecs() []*templateSpec {
+	specs := make([]*templateSpec, 0, 2)
+
+	for _, k := range []Kind{JavaScript, Output} {
+		specs = append(specs, &templateSpec{
+			kind: k,
+			repo: fmt.Sprintf("https://github.com/szkiba/xk6-template-%s.git", strings.ToLower(string(k))),
+			minVersions: map[string]string{
+				"go":  "1.21", // generics and toolchain directive in go.mod
+				"git": "2.28", // git init -b
+			},
+		})
+	}
+
+	return specs
+}
+
+func (k Kind) template() *templateSpec {
+	for _, spec := range templateSpecs() {
+		if spec.kind == k {
+			return spec
+		}
+	}
+
+	return nil
+}
+
+// MinVersion returns the minimum version of the tool that satisfies all templates.
+// It is used before the kind of the extension is known.
+func MinVersion(tool string) string {
+	var version string
+
+	for _, spec := range templateSpecs() {
+		if v, found := spec.minVersions[tool]; found && CompareVersions(v, version) > 0 {
+			version = v
+		}
+	}
+
+	return version
+}
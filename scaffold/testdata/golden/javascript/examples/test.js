@@ -0,0 +1,5 @@
+import { Foo } from "k6/x/foo";
+
+export default function () {
+  console.log(new Foo(__ENV.XK6_FOO_URL));
+}
@@ -0,0 +1,13 @@
+// Package foo contains the xk6-foo extension.
+package foo
+
+import (
+	"github.com/acme/xk6-foo/foo"
+	"go.k6.io/k6/js/modules"
+)
+
+const importPath = "k6/x/foo"
+
+func init() {
+	modules.Register(importPath, new(foo.RootModule))
+}
@@ -0,0 +1,6 @@
+package foo
+
+// Foo is the primary class of the extension.
+type Foo struct {
+	url string // from XK6_FOO_URL environment variable
+}
@@ -0,0 +1,3 @@
+module github.com/acme/xk6-foo
+
+go 1.21
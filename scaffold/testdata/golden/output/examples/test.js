@@ -0,0 +1,5 @@
+import { FancyBar } from "k6/x/fancy_bar";
+
+export default function () {
+  console.log(new FancyBar(__ENV.XK6_OUTPUT_FANCY_BAR_URL));
+}
@@ -0,0 +1,13 @@
+// Package fancy_bar contains the xk6-fancy_bar extension.
+package fancy_bar
+
+import (
+	"example.com/team/xk6-output-fancy_bar/fancy_bar"
+	"go.k6.io/k6/js/modules"
+)
+
+const importPath = "k6/x/fancy_bar"
+
+func init() {
+	modules.Register(importPath, new(fancy_bar.RootModule))
+}
@@ -0,0 +1,6 @@
+package fancy_bar
+
+// FancyBar is the primary class of the extension.
+type FancyBar struct {
+	url string // from XK6_OUTPUT_FANCY_BAR_URL environment variable
+}
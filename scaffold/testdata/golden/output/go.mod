@@ -0,0 +1,3 @@
+module example.com/team/xk6-output-fancy_bar
+
+go 1.21
@@ -0,0 +1,5 @@
+import { ˮPrimaryClassˮ } from "k6/x/ˮnameˮ";
+
+export default function () {
+  console.log(new ˮPrimaryClassˮ(__ENV.ˮenvPrefixˮ_URL));
+}
@@ -0,0 +1,3 @@
+module github.com/szkiba/xk6-template-javascript
+
+go 1.21
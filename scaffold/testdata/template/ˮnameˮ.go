@@ -0,0 +1,13 @@
+// Package ˮgoPackageˮ contains the xk6-ˮnameˮ extension.
+package ˮgoPackageˮ
+
+import (
+	"github.com/szkiba/xk6-template-javascript/ˮnameˮ"
+	"go.k6.io/k6/js/modules"
+)
+
+const importPath = "k6/x/ˮnameˮ"
+
+func init() {
+	modules.Register(importPath, new(ˮnameˮ.RootModule))
+}
@@ -0,0 +1,6 @@
+package ˮnameˮ
+
+// ˮPrimaryClassˮ is the primary class of the extension.
+type ˮPrimaryClassˮ struct {
+	url string // from ˮenvPrefixˮ_URL environment variable
+}
@@ -0,0 +1,254 @@
+package scaffold
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/module"
+)
+
+// ValidationError describes an invalid option.
+type ValidationError struct {
+	// Option is the JSON name of the invalid option.
+	Option string
+	// Value is the invalid value.
+	Value string
+	// Err is the reason.
+	Err error
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s %q: %s", e.Option, e.Value, e.Err)
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports ErrInvalidOption as the cause of any ValidationError.
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrInvalidOption //nolint:errorlint,goerr113
+}
+
+// ValidationErrors contains all the invalid options found by Options.Validate.
+type ValidationErrors []*ValidationError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+
+	for _, verr := range e {
+		msgs = append(msgs, verr.Error())
+	}
+
+	return "invalid options: " + strings.Join(msgs, "; ")
+}
+
+func (e ValidationErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+
+	for _, verr := range e {
+		errs = append(errs, verr)
+	}
+
+	return errs
+}
+
+// Validate checks the options before any side effects.
+// All the invalid options are returned at once as ValidationErrors.
+func (opts *Options) Validate() error {
+	var errs ValidationErrors
+
+	check := func(option, value string, err error) {
+		if err != nil {
+			errs = append(errs, &ValidationError{Option: option, Value: value, Err: err})
+		}
+	}
+
+	if opts.Kind.template() == nil {
+		check("kind", string(opts.Kind), ErrUnknownKind)
+	}
+
+	check("name", opts.Name, CheckName(opts.Name))
+	check("dir", opts.Dir, required(opts.Dir))
+	check("goModule", opts.GoModule, CheckGoModule(opts.GoModule))
+	check("goPackage", opts.GoPackage, CheckGoPackage(opts.GoPackage))
+
+	check("categories", strings.Join(opts.Categories, ","), CheckCategories(opts.Categories))
+
+	if opts.UseGitHub || opts.SyncGitHub || opts.Publish {
+		check("repoHost", opts.RepoHost, CheckRepoHost(opts.RepoHost))
+		check("repoOwner", opts.RepoOwner, CheckRepoOwner(opts.Host(), opts.RepoOwner))
+		check("repoName", opts.RepoName, CheckRepoName(opts.Kind, opts.RepoName))
+	}
+
+	if opts.SyncGitHub || opts.Publish {
+		check("gitHubToken", "", required(opts.GitHubToken))
+
+		if CheckRepoHost(opts.RepoHost) == nil && opts.Host().Provider != ProviderGitHub {
+			check("repoHost", opts.RepoHost, errGitHubRequired)
+		}
+	}
+
+	if opts.Publish && (opts.NoGitInit || opts.NoGitOrigin) {
+		check("publish", "true", errPublishWithoutGit)
+	}
+
+	if !opts.NoGitInit && !opts.NoGitOrigin {
+		if opts.UseGitHub {
+			check("repoProtocol", opts.RepoProtocol, CheckRepoProtocol(opts.RepoProtocol))
+		}
+
+		check("gitOrigin", opts.GitOrigin, CheckGitOrigin(opts.GitOrigin))
+	}
+
+	check("ci", opts.CI, CheckCI(opts.CI))
+
+	if len(opts.CI) != 0 && CheckCI(opts.CI) == nil && len(opts.CIPlatform()) == 0 {
+		check("ci", opts.CI, fmt.Errorf("%w %s", errNoCI, opts.Host().Title()))
+	}
+
+	steps := make([]string, 0, len(opts.StepTimeouts))
+
+	for step := range opts.StepTimeouts {
+		steps = append(steps, step)
+	}
+
+	sort.Strings(steps)
+
+	for _, step := range steps {
+		check("stepTimeouts", step, CheckStep(step))
+	}
+
+	if len(errs) != 0 {
+		return errs
+	}
+
+	return nil
+}
+
+func required(value string) error {
+	if len(value) == 0 {
+		return errRequired
+	}
+
+	return nil
+}
+
+// CheckName checks the extension name: 3 to 32 lower case alphanumeric characters.
+func CheckName(name string) error {
+	if err := required(name); err != nil {
+		return err
+	}
+
+	if !reName.MatchString(name) {
+		return errInvalidName
+	}
+
+	return nil
+}
+
+// CheckGoModule checks the go module path syntax.
+// Local module paths without a dot in the first path element (like xk6-foo) are also accepted.
+func CheckGoModule(path string) error {
+	if err := required(path); err != nil {
+		return err
+	}
+
+	if first, _, _ := strings.Cut(path, "/"); !strings.Contains(first, ".") {
+		return module.CheckImportPath(path)
+	}
+
+	return module.CheckPath(path)
+}
+
+// CheckGoPackage checks that the go package name is an identifier and not a keyword.
+func CheckGoPackage(name string) error {
+	if err := required(name); err != nil {
+		return err
+	}
+
+	if !token.IsIdentifier(name) {
+		return errNotIdentifier
+	}
+
+	return nil
+}
+
+// CheckRepoName checks that the repository name starts with the prefix of the extension type.
+func CheckRepoName(k Kind, name string) error {
+	if err := required(name); err != nil {
+		return err
+	}
+
+	prefix := k.RepoNamePrefix()
+
+	if !strings.HasPrefix(name, prefix) || len(name) == len(prefix) {
+		return fmt.Errorf("%w %s", errMissingPrefix, prefix)
+	}
+
+	return nil
+}
+
+// CheckRepoProtocol checks that the git origin protocol is ssh or https.
+func CheckRepoProtocol(protocol string) error {
+	if protocol != "ssh" && protocol != "https" {
+		return errInvalidProtocol
+	}
+
+	return nil
+}
+
+// CheckGitOrigin checks the git URL syntax: either an URL (like https://host/path.git)
+// or the scp-like syntax (like git@host:path.git).
+func CheckGitOrigin(origin string) error {
+	if err := required(origin); err != nil {
+		return err
+	}
+
+	if reSCPLike.MatchString(origin) {
+		return nil
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil {
+		return errInvalidGitURL
+	}
+
+	switch u.Scheme {
+	case "https", "http", "ssh", "git":
+		if len(u.Host) != 0 && len(strings.Trim(u.Path, "/")) != 0 {
+			return nil
+		}
+	case "file":
+		if len(u.Path) != 0 {
+			return nil
+		}
+	}
+
+	return errInvalidGitURL
+}
+
+var (
+	reName    = regexp.MustCompile(`^[a-z0-9]{3,32}$`)               //nolint:gochecknoglobals
+	reSCPLike = regexp.MustCompile(`^[\w.-]+@[\w.-]+:[^/\s][^\s]*$`) //nolint:gochecknoglobals
+)
+
+var (
+	// ErrInvalidOption is the cause of the errors returned by Options.Validate.
+	ErrInvalidOption = errors.New("invalid option")
+
+	errRequired        = errors.New("value is required")
+	errInvalidName     = errors.New("minimum 3 maximum 32 lower case alphanumeric characters are required")
+	errNotIdentifier   = errors.New("valid go identifier (not a keyword) is required")
+	errMissingPrefix   = errors.New("the name must start with")
+	errInvalidProtocol = errors.New("ssh or https is required")
+	errInvalidGitURL   = errors.New("git URL (like https://host/path.git or git@host:path.git) is required")
+
+	errPublishWithoutGit = errors.New("git repository initialization and git origin are required")
+	errGitHubRequired    = errors.New("GitHub host is required to sync and publish the repository")
+)
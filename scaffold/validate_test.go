@@ -0,0 +1,158 @@
+package scaffold
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_checks(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		check func(string) error
+		valid []string
+		wrong []string
+	}{
+		{
+			name:  "name",
+			check: CheckName,
+			valid: []string{"foo", "sql2", "abcdefghijklmnopqrstuvwxyz012345"},
+			wrong: []string{"", "fo", "Foo", "foo_bar", "foo-bar", "abcdefghijklmnopqrstuvwxyz0123456"},
+		},
+		{
+			name:  "goModule",
+			check: CheckGoModule,
+			valid: []string{"github.com/acme/xk6-foo", "example.com/team/xk6-output-bar", "xk6-foo"},
+			wrong: []string{"", "GitHub.com/acme/xk6-foo", "github.com/acme/xk6 foo", "/xk6-foo", "github.com//xk6-foo", "github.com/acme/"},
+		},
+		{
+			name:  "goPackage",
+			check: CheckGoPackage,
+			valid: []string{"foo", "fancy_bar", "_foo"},
+			wrong: []string{"", "func", "type", "9lives", "foo-bar"},
+		},
+		{
+			name:  "repoProtocol",
+			check: CheckRepoProtocol,
+			valid: []string{"ssh", "https"},
+			wrong: []string{"", "http", "git"},
+		},
+		{
+			name:  "gitOrigin",
+			check: CheckGitOrigin,
+			valid: []string{
+				"git@github.com:acme/xk6-foo.git",
+				"https://github.com/acme/xk6-foo.git",
+				"ssh://git@example.com:2222/acme/xk6-foo.git",
+				"file:///srv/git/xk6-foo.git",
+			},
+			wrong: []string{"", "github.com/acme/xk6-foo", "https://github.com", "ftp://example.com/xk6-foo.git", "git@github.com"},
+		},
+		{
+			name:  "step",
+			check: CheckStep,
+			valid: []string{"Install xk6", "install-xk6", "resolve-k6-version", " Pin k6 version", "verify_go_test"},
+			wrong: []string{"", "download-templat", "install"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			for _, value := range tt.valid {
+				assert.NoError(t, tt.check(value), value)
+			}
+
+			for _, value := range tt.wrong {
+				assert.Error(t, tt.check(value), value)
+			}
+		})
+	}
+}
+
+func Test_CheckRepoName(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, CheckRepoName(JavaScript, "xk6-foo"))
+	assert.NoError(t, CheckRepoName(Output, "xk6-output-foo"))
+	assert.Error(t, CheckRepoName(JavaScript, "k6-foo"))
+	assert.Error(t, CheckRepoName(JavaScript, "xk6-"))
+	assert.Error(t, CheckRepoName(Output, "xk6-foo"))
+}
+
+func Test_Options_Validate(t *testing.T) {
+	t.Parallel()
+
+	opts := &Options{Kind: JavaScript, Name: "foo", RepoOwner: "acme", RepoProtocol: "ssh"}
+
+	opts.Guess()
+
+	require.NoError(t, opts.Validate())
+
+	opts.Name = "Foo"
+	opts.GoPackage = "func"
+	opts.RepoProtocol = "ftp"
+	opts.GitOrigin = ""
+	opts.StepTimeouts = map[string]time.Duration{"install-xk6": time.Minute, "download-templat": time.Minute}
+
+	err := opts.Validate()
+
+	require.ErrorIs(t, err, ErrInvalidOption)
+
+	var verrs ValidationErrors
+
+	require.True(t, errors.As(err, &verrs))
+
+	options := make([]string, 0, len(verrs))
+
+	for _, verr := range verrs {
+		options = append(options, verr.Option)
+	}
+
+	assert.Equal(t, []string{"name", "goPackage", "repoProtocol", "gitOrigin", "stepTimeouts"}, options)
+	assert.Contains(t, err.Error(), `name "Foo": minimum 3 maximum 32`)
+	assert.Contains(t, err.Error(), `stepTimeouts "download-templat": unknown step`)
+
+	opts.NoGitOrigin = true
+	opts.StepTimeouts = nil
+	opts.Name = "foo"
+	opts.GoPackage = "foo"
+
+	assert.NoError(t, opts.Validate())
+}
+
+// Test_Validate_readme checks the options of the Library example in README.md.
+func Test_Validate_readme(t *testing.T) {
+	t.Parallel()
+
+	opts := &Options{
+		Kind:         JavaScript,
+		Name:         "foo",
+		RepoOwner:    "acme",
+		RepoProtocol: "ssh",
+		OnEvent:      func(*Event) {},
+	}
+
+	opts.Guess()
+	opts.Update()
+
+	require.NoError(t, opts.Validate())
+	assert.Equal(t, "git@github.com:acme/xk6-foo.git", opts.GitOrigin)
+	assert.Equal(t, "github.com/acme/xk6-foo", opts.GoModule)
+	assert.Equal(t, "xk6-foo", opts.Dir)
+
+	opts.RepoProtocol = ""
+	opts.GitOrigin = ""
+
+	opts.Guess()
+
+	assert.Error(t, opts.Validate())
+}
@@ -0,0 +1,66 @@
+package scaffold
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ParseVersion extracts the first dot separated version number from a tool's version output.
+// For example "go version go1.21.5 linux/amd64" results "1.21.5".
+func ParseVersion(out string) (string, error) {
+	match := reVersion.FindStringSubmatch(out)
+	if match == nil {
+		return "", fmt.Errorf("%w: %q", errUnknownVersion, strings.TrimSpace(out))
+	}
+
+	return match[1], nil
+}
+
+// CompareVersions compares two dot separated version numbers.
+// Missing components are considered zero, so "1.21" equals to "1.21.0".
+func CompareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+
+	for len(as) < len(bs) {
+		as = append(as, "0")
+	}
+
+	for len(bs) < len(as) {
+		bs = append(bs, "0")
+	}
+
+	for idx := range as {
+		an, _ := strconv.Atoi(as[idx]) //nolint:errcheck
+		bn, _ := strconv.Atoi(bs[idx]) //nolint:errcheck
+
+		if an != bn {
+			if an < bn {
+				return -1
+			}
+
+			return 1
+		}
+	}
+
+	return 0
+}
+
+// GoModVersion returns the go version required by the go.mod file content.
+func GoModVersion(gomod []byte) (string, error) {
+	match := reGoModVersion.FindSubmatch(gomod)
+	if match == nil {
+		return "", fmt.Errorf("%w: missing go directive", errUnknownVersion)
+	}
+
+	return string(match[1]), nil
+}
+
+var (
+	reVersion      = regexp.MustCompile(`v?(\d+\.\d+(?:\.\d+)?)`)         //nolint:gochecknoglobals
+	reGoModVersion = regexp.MustCompile(`(?m)^go\s+(\d+\.\d+(?:\.\d+)?)`) //nolint:gochecknoglobals
+)
+
+var errUnknownVersion = errors.New("unknown version")
@@ -0,0 +1,61 @@
+package scaffold
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ParseVersion(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"go version go1.21.5 linux/amd64":         "1.21.5",
+		"git version 2.39.2":                      "2.39.2",
+		"git version 2.39.3 (Apple Git-145)":      "2.39.3",
+		"k6 v0.48.0 (go1.21.5, linux/amd64)":      "0.48.0",
+		"go version go1.22 darwin/arm64":          "1.22",
+		"xk6 version v0.10.0 (commit: abcdef123)": "0.10.0",
+	}
+
+	for out, expected := range tests {
+		actual, err := ParseVersion(out)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual, out)
+	}
+
+	_, err := ParseVersion("unknown")
+
+	assert.ErrorIs(t, err, errUnknownVersion)
+}
+
+func Test_CompareVersions(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 0, CompareVersions("1.21", "1.21.0"))
+	assert.Equal(t, -1, CompareVersions("1.21.5", "1.22"))
+	assert.Equal(t, 1, CompareVersions("1.10", "1.9.9"))
+	assert.Equal(t, -1, CompareVersions("2.27", "2.28.0"))
+}
+
+func Test_GoModVersion(t *testing.T) {
+	t.Parallel()
+
+	version, err := GoModVersion([]byte("module example.com/xk6-foo\n\ngo 1.21.4\n\ntoolchain go1.22.0\n"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "1.21.4", version)
+
+	_, err = GoModVersion([]byte("module example.com/xk6-foo\n"))
+
+	assert.ErrorIs(t, err, errUnknownVersion)
+}
+
+func Test_MinVersion(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "1.21", MinVersion("go"))
+	assert.Equal(t, "2.28", MinVersion("git"))
+	assert.Empty(t, MinVersion("xk6"))
+}
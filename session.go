@@ -0,0 +1,33 @@
+//nolint:forbidigo
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AlecAivazis/survey/v2/terminal"
+	"github.com/szkiba/create-k6-extension/scaffold"
+)
+
+// session runs the steps of a command and reports their progress.
+type session struct {
+	*terminal.Stdio
+	*scaffold.Pipeline
+	opts *options
+}
+
+func newSession(ctx context.Context, opts *options, rt *runtime) *session {
+	return &session{
+		Stdio:    rt.Stdio,
+		Pipeline: scaffold.NewPipeline(ctx, rt.executor, notify(newReporter(opts, rt))),
+		opts:     opts,
+	}
+}
+
+func (s *session) print(format string, a ...any) {
+	fmt.Fprintf(s.Out, format, a...)
+}
+
+func (s *session) runGoGenerate() error {
+	return s.Run(s.opts.Dir, "go", "generate", "./...")
+}